@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"reflect"
+	"time"
 )
 
 var (
@@ -17,8 +18,27 @@ var (
 
 	// ErrNoPK is returned from various methods when primary key is required and not set.
 	ErrNoPK = errors.New("reform: no primary key")
+
+	// ErrStaleRecord is returned from Update, UpdateColumns, and Save methods for VersionedTable records
+	// when row exists, but its version column value doesn't match record's one.
+	ErrStaleRecord = errors.New("reform: stale record")
+
+	// ErrNothingToUpdate is returned from UpdateColumns and UpdateView methods when there are no columns to update.
+	ErrNothingToUpdate = errors.New("reform: nothing to update")
+
+	// ErrNoConflictColumns is returned from Upsert-like methods when conflict columns are not given.
+	ErrNoConflictColumns = errors.New("reform: no conflict columns")
+
+	// ErrInvalidPageCursor is returned from SelectPage when page cursor is malformed
+	// or was returned for a different order.
+	ErrInvalidPageCursor = errors.New("reform: invalid page cursor")
 )
 
+// Column is a column name of SQL database view or table.
+// Generated code provides column descriptors for each view and table, for example, PersonTable.C.Email.
+// They can be used with package where for building typed query tails.
+type Column string
+
 // View represents SQL database view or table.
 type View interface {
 	// Schema returns a schema name in SQL database.
@@ -34,7 +54,7 @@ type View interface {
 	NewStruct() Struct
 }
 
-// Table represents SQL database table with single-column primary key.
+// Table represents SQL database table with single-column or composite primary key.
 // It extends View.
 type Table interface {
 	View
@@ -43,7 +63,80 @@ type Table interface {
 	NewRecord() Record
 
 	// PKColumnIndex returns an index of primary key column for that table in SQL database.
+	// For composite primary key, it returns an index of the first primary key column.
 	PKColumnIndex() uint
+
+	// PKColumnIndexes returns a new slice of indexes of all primary key columns for that table in SQL database.
+	// For single-column primary key, it contains exactly one element.
+	PKColumnIndexes() []uint
+}
+
+// VersionedTable is an optional interface for Table with version column used for optimistic locking.
+// It is implemented by generated code for structs with field with "version" label in "reform:" tag.
+// It extends Table.
+type VersionedTable interface {
+	Table
+
+	// VersionColumnIndex returns an index of version column for that table in SQL database.
+	VersionColumnIndex() uint
+}
+
+// SoftDeleteTable is an optional interface for Table with soft delete timestamp column.
+// It is implemented by generated code for structs with field with "softdelete" label in "reform:" tag.
+// It extends Table.
+//
+// Querier's Delete and DeleteFrom methods set that column instead of deleting rows,
+// and selectors exclude rows with that column set. See Querier.WithDeleted and Querier.HardDelete.
+type SoftDeleteTable interface {
+	Table
+
+	// SoftDeleteColumnIndex returns an index of soft delete column for that table in SQL database.
+	SoftDeleteColumnIndex() uint
+}
+
+// AutoCreateTable is an optional interface for Table with timestamp column set on insert.
+// It is implemented by generated code for structs with field with "autocreate" label in "reform:" tag.
+// It extends Table.
+//
+// Querier's Insert-like methods set that column to the current time (see DB.SetClock and DB.SetTimePrecision)
+// if it is not set yet.
+type AutoCreateTable interface {
+	Table
+
+	// AutoCreateColumnIndex returns an index of column set on insert for that table in SQL database.
+	AutoCreateColumnIndex() uint
+}
+
+// AutoUpdateTable is an optional interface for Table with timestamp column set on update.
+// It is implemented by generated code for structs with field with "autoupdate" label in "reform:" tag.
+// It extends Table.
+//
+// Querier's Update-like methods always set that column to the current time (see DB.SetClock
+// and DB.SetTimePrecision). Insert-like methods do not change it.
+type AutoUpdateTable interface {
+	Table
+
+	// AutoUpdateColumnIndex returns an index of column set on update for that table in SQL database.
+	AutoUpdateColumnIndex() uint
+}
+
+// ForeignKey describes a foreign key column of SQL database view or table.
+type ForeignKey struct {
+	Column    string // column of that view or table
+	RefTable  string // referenced table name, qualified with schema if it is not default
+	RefColumn string // referenced column
+}
+
+// ForeignKeysView is an optional interface for View with foreign key columns.
+// It is implemented by generated code for structs with fields with "fk=table.column" label in "reform:" tag.
+// It extends View.
+//
+// Foreign keys are used by Querier.Preload.
+type ForeignKeysView interface {
+	View
+
+	// ForeignKeys returns a new slice of foreign keys of that view or table.
+	ForeignKeys() []ForeignKey
 }
 
 // Struct represents a row in SQL database view or table.
@@ -63,7 +156,7 @@ type Struct interface {
 	View() View
 }
 
-// Record represents a row in SQL database table with single-column primary key.
+// Record represents a row in SQL database table with single-column or composite primary key.
 type Record interface {
 	Struct
 
@@ -71,14 +164,18 @@ type Record interface {
 	Table() Table
 
 	// PKValue returns a value of primary key for that record.
+	// For composite primary key, it returns []interface{} with values of all primary key fields.
 	// Returned interface{} value is never untyped nil.
 	PKValue() interface{}
 
 	// PKPointer returns a pointer to primary key field for that record.
+	// For composite primary key, it returns []interface{} with pointers to all primary key fields.
 	// Returned interface{} value is never untyped nil.
 	PKPointer() interface{}
 
 	// HasPK returns true if record has non-zero primary key set, false otherwise.
+	// For composite primary key, at least one field should be non-zero, so zero values of other fields
+	// are valid parts of the key.
 	HasPK() bool
 
 	// SetPK sets record primary key, if possible.
@@ -87,6 +184,17 @@ type Record interface {
 	SetPK(pk interface{})
 }
 
+// TailExpression is an expression which renders into query tail and its arguments.
+// It is implemented by package where.
+//
+// Querier methods accepting tail and args also accept TailExpression as a single argument.
+// In that case, it is rendered with Querier's dialect and appended to tail, which is typically empty.
+type TailExpression interface {
+	// Tail returns SQL query tail for given dialect with placeholders starting from given index,
+	// and arguments for them.
+	Tail(dialect Dialect, start int) (tail string, args []interface{})
+}
+
 // BeforeInserter is an optional interface for Record which is used by Querier.Insert.
 // It can be used to set record's timestamp fields, convert timezones, change data precision, etc.
 // Returning error aborts operation.
@@ -94,6 +202,13 @@ type BeforeInserter interface {
 	BeforeInsert() error
 }
 
+// BeforeInserterContext is a variant of BeforeInserter which receives Querier's context and Querier itself
+// (bound to the same transaction, if any), for example, to fill audit fields or to make additional queries.
+// If struct implements it, BeforeInserter is not used.
+type BeforeInserterContext interface {
+	BeforeInsertContext(ctx context.Context, q *Querier) error
+}
+
 // BeforeUpdater is an optional interface for Record which is used by Querier.Update and Querier.UpdateColumns.
 // It can be used to set record's timestamp fields, convert timezones, change data precision, etc.
 // Returning error aborts operation.
@@ -101,6 +216,83 @@ type BeforeUpdater interface {
 	BeforeUpdate() error
 }
 
+// BeforeUpdaterContext is a variant of BeforeUpdater with context and Querier, see BeforeInserterContext.
+// If struct implements it, BeforeUpdater is not used.
+type BeforeUpdaterContext interface {
+	BeforeUpdateContext(ctx context.Context, q *Querier) error
+}
+
+// AfterInserter is an optional interface for Record which is used by Querier.Insert, Querier.InsertColumns,
+// Querier.InsertMulti, Querier.Upsert, Querier.UpsertMulti, and Querier.Save.
+// It is called after successful INSERT and after filling record's primary key field (if it is filled by method).
+// It can be used to update caches, send notifications, etc.
+// Returning error is returned from method, but does not undo operation.
+type AfterInserter interface {
+	AfterInsert() error
+}
+
+// AfterInserterContext is a variant of AfterInserter with context and Querier, see BeforeInserterContext.
+// If struct implements it, AfterInserter is not used.
+type AfterInserterContext interface {
+	AfterInsertContext(ctx context.Context, q *Querier) error
+}
+
+// AfterUpdater is an optional interface for Record which is used by Querier.Update, Querier.UpdateColumns,
+// Querier.UpdateView, and Querier.Save.
+// It is called after successful UPDATE and after incrementing VersionedTable record's version field.
+// It can be used to update caches, send notifications, etc.
+// Returning error is returned from method, but does not undo operation.
+type AfterUpdater interface {
+	AfterUpdate() error
+}
+
+// AfterUpdaterContext is a variant of AfterUpdater with context and Querier, see BeforeInserterContext.
+// If struct implements it, AfterUpdater is not used.
+type AfterUpdaterContext interface {
+	AfterUpdateContext(ctx context.Context, q *Querier) error
+}
+
+// AfterSaver is an optional interface for Record which is used by Querier.Save, Querier.SaveColumns,
+// Querier.Upsert, and Querier.UpsertMulti.
+// It is called after successful update or insert, and after AfterUpdate() or AfterInsert().
+// Returning error is returned from method, but does not undo operation.
+type AfterSaver interface {
+	AfterSave() error
+}
+
+// AfterSaverContext is a variant of AfterSaver with context and Querier, see BeforeInserterContext.
+// If struct implements it, AfterSaver is not used.
+type AfterSaverContext interface {
+	AfterSaveContext(ctx context.Context, q *Querier) error
+}
+
+// BeforeDeleter is an optional interface for Record which is used by Querier.Delete and Querier.HardDelete.
+// It can be used to prevent deletion of protected rows.
+// Returning error aborts operation.
+type BeforeDeleter interface {
+	BeforeDelete() error
+}
+
+// BeforeDeleterContext is a variant of BeforeDeleter with context and Querier, see BeforeInserterContext.
+// If struct implements it, BeforeDeleter is not used.
+type BeforeDeleterContext interface {
+	BeforeDeleteContext(ctx context.Context, q *Querier) error
+}
+
+// AfterDeleter is an optional interface for Record which is used by Querier.Delete and Querier.HardDelete.
+// It is called after successful DELETE (or UPDATE for soft delete, after setting record's soft delete field).
+// It can be used to update caches, send notifications, etc.
+// Returning error is returned from method, but does not undo operation.
+type AfterDeleter interface {
+	AfterDelete() error
+}
+
+// AfterDeleterContext is a variant of AfterDeleter with context and Querier, see BeforeInserterContext.
+// If struct implements it, AfterDeleter is not used.
+type AfterDeleterContext interface {
+	AfterDeleteContext(ctx context.Context, q *Querier) error
+}
+
 // AfterFinder is an optional interface for Record which is used by Querier's finders and selectors.
 // It can be used to convert timezones, change data precision, etc.
 // Returning error aborts operation.
@@ -108,6 +300,12 @@ type AfterFinder interface {
 	AfterFind() error
 }
 
+// AfterFinderContext is a variant of AfterFinder with context and Querier, see BeforeInserterContext.
+// If struct implements it, AfterFinder is not used.
+type AfterFinderContext interface {
+	AfterFindContext(ctx context.Context, q *Querier) error
+}
+
 // DBTX is an interface for database connection or transaction.
 // It's implemented by *sql.DB, *sql.Tx, *DB, *TX, and *Querier.
 type DBTX interface {
@@ -158,6 +356,24 @@ const (
 	OutputInserted
 )
 
+// LastInsertIdMultiMethod is a method of receiving primary keys of rows inserted by a single multi-row INSERT
+// for dialects using LastInsertId method.
+type LastInsertIdMultiMethod int
+
+const (
+	// NoLastInsertIdMulti is a method of dialects where primary keys of rows inserted by multi-row INSERT
+	// can't be received.
+	NoLastInsertIdMulti LastInsertIdMultiMethod = iota
+
+	// FirstInsertId is a method of dialects where sql.Result.LastInsertId() returns primary key of the first
+	// inserted row, and other rows get consecutive primary keys if server settings allow that (MySQL).
+	FirstInsertId
+
+	// LastRowInsertId is a method of dialects where sql.Result.LastInsertId() returns primary key of the last
+	// inserted row, and other rows always get consecutive primary keys (SQLite3).
+	LastRowInsertId
+)
+
 // SelectLimitMethod is a method of limiting the number of rows in a query result.
 type SelectLimitMethod int
 
@@ -180,6 +396,33 @@ const (
 	EmptyLists
 )
 
+// UpsertMethod is a method of inserting row or updating existing one in case of conflict.
+type UpsertMethod int
+
+const (
+	// OnConflict is a method using "INSERT ... ON CONFLICT (columns) DO UPDATE SET ..." SQL syntax.
+	OnConflict UpsertMethod = iota
+
+	// OnDuplicateKeyUpdate is a method using "INSERT ... ON DUPLICATE KEY UPDATE ..." SQL syntax.
+	OnDuplicateKeyUpdate
+
+	// Merge is a method using "MERGE INTO ... USING ... WHEN MATCHED ... WHEN NOT MATCHED ..." SQL syntax.
+	Merge
+)
+
+// SavepointMethod is a method of creating, rolling back to and releasing savepoints in transaction.
+type SavepointMethod int
+
+const (
+	// Savepoint is a method using "SAVEPOINT name", "ROLLBACK TO SAVEPOINT name",
+	// and "RELEASE SAVEPOINT name" SQL syntax.
+	Savepoint SavepointMethod = iota
+
+	// SaveTransaction is a method using "SAVE TRANSACTION name" and "ROLLBACK TRANSACTION name" SQL syntax.
+	// Savepoints can't be released.
+	SaveTransaction
+)
+
 // Dialect represents differences in various SQL dialects.
 type Dialect interface {
 	// String returns dialect name.
@@ -200,19 +443,91 @@ type Dialect interface {
 	// LastInsertIdMethod returns a method of receiving primary key of last inserted row.
 	LastInsertIdMethod() LastInsertIdMethod
 
+	// LastInsertIdMultiMethod returns a method of receiving primary keys of rows inserted by multi-row INSERT.
+	// It is used only if LastInsertIdMethod returns LastInsertId.
+	LastInsertIdMultiMethod() LastInsertIdMultiMethod
+
 	// SelectLimitMethod returns a method of limiting the number of rows in a query result.
 	SelectLimitMethod() SelectLimitMethod
 
 	// DefaultValuesMethod returns a method of inserting of row with all default values.
 	DefaultValuesMethod() DefaultValuesMethod
+
+	// UpsertMethod returns a method of inserting row or updating existing one in case of conflict.
+	UpsertMethod() UpsertMethod
+
+	// SavepointMethod returns a method of creating, rolling back to and releasing savepoints in transaction.
+	SavepointMethod() SavepointMethod
+
+	// MaxPlaceholders returns the maximum number of placeholder parameters in a single query,
+	// or 0 if there is no limit.
+	MaxPlaceholders() int
+}
+
+// ErrorKind is a kind of error returned by SQL database driver.
+type ErrorKind int
+
+const (
+	// OtherError is a kind of errors not described below.
+	OtherError ErrorKind = iota
+
+	// UniqueViolation is a kind of errors caused by violation of unique or primary key constraint.
+	UniqueViolation
+
+	// ForeignKeyViolation is a kind of errors caused by violation of foreign key constraint.
+	ForeignKeyViolation
+
+	// NotNullViolation is a kind of errors caused by violation of NOT NULL constraint.
+	NotNullViolation
+
+	// SerializationFailure is a kind of errors caused by concurrent update conflicts in transaction.
+	SerializationFailure
+
+	// Deadlock is a kind of errors caused by detected deadlock, or by lock wait timeout
+	// which may be caused by undetected one.
+	Deadlock
+)
+
+// ErrorClassifier is an optional interface for Dialect which classifies errors returned by SQL database drivers.
+type ErrorClassifier interface {
+	// ClassifyError returns a kind of given error (which may wrap driver's error), and a name of violated
+	// constraint (or column for NotNullViolation) if driver exposes it.
+	ClassifyError(err error) (kind ErrorKind, constraint string)
+}
+
+// ReplicationLagProber is an optional interface for Dialect which measures replication lag.
+// It is used by ReplicaPool health checks (see ReplicaPool.SetMaxLag).
+type ReplicationLagProber interface {
+	// ReplicationLag returns replication lag of SQL database accessed via given connection,
+	// or zero if it is not a replica.
+	ReplicationLag(ctx context.Context, db DBTXContext) (time.Duration, error)
 }
 
 // SetPK sets record's primary key, if possible.
+// For composite primary key, pk should be []interface{} with values for all primary key fields.
 //
 // Deprecated: prefer direct field assignment where possible.
 func SetPK(r Record, pk interface{}) {
-	fV := reflect.ValueOf(r.Pointers()[r.Table().PKColumnIndex()]).Elem()
-	pkV := reflect.ValueOf(pk)
+	pointers := r.Pointers()
+	indexes := r.Table().PKColumnIndexes()
+	if len(indexes) == 1 {
+		setField(pointers[indexes[0]], pk)
+		return
+	}
+
+	values, ok := pk.([]interface{})
+	if !ok || len(values) != len(indexes) {
+		return
+	}
+	for i, index := range indexes {
+		setField(pointers[index], values[i])
+	}
+}
+
+// setField sets field by pointer to given value, if possible.
+func setField(pointer interface{}, value interface{}) {
+	fV := reflect.ValueOf(pointer).Elem()
+	pkV := reflect.ValueOf(value)
 	if t := fV.Type(); t.ConvertibleTo(pkV.Type()) {
 		fV.Set(pkV.Convert(t))
 	}
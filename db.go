@@ -3,6 +3,8 @@ package reform
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"math/rand"
 	"time"
 )
 
@@ -40,8 +42,10 @@ func NewDB(db *sql.DB, dialect Dialect, logger Logger) *DB {
 // Can be used for easier integration with existing code or for passing test doubles.
 // Logger can be nil.
 func NewDBFromInterface(db DBInterface, dialect Dialect, logger Logger) *DB {
+	q := newQuerier(context.Background(), db, "", dialect, logger, false, newReplicaPool(dialect), nil)
+	q.autoIncrement = new(autoIncrementMode)
 	return &DB{
-		Querier: newQuerier(context.Background(), db, "", dialect, logger, false, nil, nil),
+		Querier: q,
 		db:      db,
 	}
 }
@@ -51,11 +55,52 @@ func (db *DB) DBInterface() DBInterface {
 	return db.db
 }
 
-// AddSlaves adds slave *sql.DB connections.
+// AddSlaves adds slave *sql.DB connections to DB's ReplicaPool with names "slave1", "slave2", etc. and weight 1.
 func (db *DB) AddSlaves(slaves ...*sql.DB) {
-	for _, s := range slaves {
-		db.slaves = append(db.slaves, newQuerier(context.Background(), s, "", db.Dialect, db.Logger, false, nil, nil))
-	}
+	db.replicas.addSlaves(slaves)
+}
+
+// Replicas returns DB's ReplicaPool. It is shared by DB and all Queriers returned by DB's methods.
+func (db *DB) Replicas() *ReplicaPool {
+	return db.replicas
+}
+
+// SetClock sets function returning the current time which is used for AutoCreateTable, AutoUpdateTable,
+// and SoftDeleteTable columns. Nil clock resets it to time.Now. Returned time is converted to UTC.
+//
+// It affects DB and transactions started after that call, but not Queriers returned by DB's methods before it.
+// It is not safe for concurrent use with other DB methods.
+func (db *DB) SetClock(clock func() time.Time) {
+	db.clock = clock
+}
+
+// SetTimePrecision sets precision of the current time which is used for AutoCreateTable, AutoUpdateTable,
+// and SoftDeleteTable columns (for example, time.Second or time.Microsecond, depending on SQL database column type).
+// Zero precision (default) disables truncation.
+//
+// It affects DB and transactions started after that call, but not Queriers returned by DB's methods before it.
+// It is not safe for concurrent use with other DB methods.
+func (db *DB) SetTimePrecision(precision time.Duration) {
+	db.timePrecision = precision
+}
+
+// AddInterceptors adds interceptors for operations made by DB, its Queriers and transactions.
+// The first added interceptor is the outermost one.
+//
+// It affects DB and transactions started after that call, but not Queriers returned by DB's methods before it.
+// It is not safe for concurrent use with other DB methods.
+func (db *DB) AddInterceptors(interceptors ...Interceptor) {
+	db.interceptors = append(db.interceptors[:len(db.interceptors):len(db.interceptors)], interceptors...)
+}
+
+// SetRoutingPolicy sets policy which decides whether queries made outside of transaction are sent to master
+// or slave connections (see AddSlaves). Nil policy resets it to DefaultRoutingPolicy.
+// Querier's OnMaster and OnReplica methods override it.
+//
+// It affects DB, but not Queriers returned by DB's methods before that call.
+// It is not safe for concurrent use with other DB methods.
+func (db *DB) SetRoutingPolicy(policy RoutingPolicy) {
+	db.routing = policy
 }
 
 // Begin starts transaction with Querier's context and default options.
@@ -64,15 +109,60 @@ func (db *DB) Begin() (*TX, error) {
 }
 
 // BeginTx starts transaction with given context and options (can be nil).
+//
+// Read-only transactions (with opts.ReadOnly set) are started on a healthy replica from DB's ReplicaPool,
+// if there is one, unless that is disabled with ReplicaPool.SetReadOnlyTransactionsOnMaster,
+// or context reads own writes (see WithReadYourWrites). Use TX's Replica method to check that.
 func (db *DB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*TX, error) {
-	db.logBefore("BEGIN", nil)
-	start := time.Now()
-	tx, err := db.db.BeginTx(ctx, opts)
-	db.logAfter("BEGIN", nil, time.Since(start), err)
-	if err != nil {
-		return nil, err
+	if r := db.readOnlyReplica(ctx, opts); r != nil {
+		start := time.Now()
+		t, err := db.beginTx(ctx, r.db, opts, r.name)
+		if !db.replicas.record(r, time.Since(start), err, true) {
+			return t, err
+		}
+	}
+
+	return db.beginTx(ctx, db.db, opts, "")
+}
+
+// readOnlyReplica returns a replica for transaction with given context and options, or nil if it should
+// be started on master.
+func (db *DB) readOnlyReplica(ctx context.Context, opts *sql.TxOptions) *replica {
+	if opts == nil || !opts.ReadOnly || db.replicas == nil || readYourWritesFrom(ctx).active() {
+		return nil
 	}
-	return newTX(ctx, tx, db.Dialect, db.Logger), nil
+	return db.replicas.pickForTransaction()
+}
+
+// beginTx starts transaction on given master or replica (with given name) connection.
+func (db *DB) beginTx(ctx context.Context, conn DBInterface, opts *sql.TxOptions, replica string) (*TX, error) {
+	q := db.WithContext(ctx)
+	call := Call{Op: OpBegin, Query: "BEGIN", Tag: q.tag, Replica: replica}
+	res := q.intercept(call, func(ctx context.Context, call Call) CallResult {
+		query := call.Query + replicaComment(replica)
+		q.logBefore(query, call.Args)
+		start := time.Now()
+
+		tx, err := conn.BeginTx(ctx, opts)
+		q.logAfter(query, call.Args, time.Since(start), err)
+		if err != nil {
+			return CallResult{Err: err}
+		}
+		return CallResult{TX: tx}
+	})
+	if res.Err != nil {
+		return nil, res.Err
+	}
+
+	t := newTX(ctx, res.TX, db.Dialect, db.Logger)
+	t.clock = db.clock
+	t.timePrecision = db.timePrecision
+	t.replica = replica
+	t.interceptors = db.interceptors
+	if replica == "" {
+		t.autoIncrement = db.autoIncrement
+	}
+	return t, nil
 }
 
 // InTransaction wraps function execution in transaction with Querier's context and default options,
@@ -84,10 +174,20 @@ func (db *DB) InTransaction(f func(t *TX) error) error {
 // InTransactionContext wraps function execution in transaction with given context and options (can be nil),
 // rolling back it in case of error or panic, committing otherwise.
 func (db *DB) InTransactionContext(ctx context.Context, opts *sql.TxOptions, f func(t *TX) error) error {
-	tx, err := db.BeginTx(ctx, opts)
+	onCommitCalls, err := db.inTransaction(ctx, opts, f)
 	if err != nil {
 		return err
 	}
+	return runOnCommitCalls(onCommitCalls)
+}
+
+// inTransaction wraps function execution in transaction, rolling back it in case of error or panic,
+// committing otherwise. It returns functions added with AddOnCommitCall for committed transaction.
+func (db *DB) inTransaction(ctx context.Context, opts *sql.TxOptions, f func(t *TX) error) ([]func() error, error) {
+	tx, err := db.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
 
 	var committed bool
 	defer func() {
@@ -101,22 +201,97 @@ func (db *DB) InTransactionContext(ctx context.Context, opts *sql.TxOptions, f f
 	if err == nil {
 		err = tx.Commit()
 	}
-	if err == nil {
-		committed = true
-		for _, call := range tx.Querier.onCommitCalls {
-			if e := call(); e != nil {
-				return e
-			}
+	if err != nil {
+		return nil, err
+	}
+	committed = true
+	return tx.Querier.onCommitCalls, nil
+}
+
+// runOnCommitCalls calls given functions until the first error.
+func runOnCommitCalls(calls []func() error) error {
+	for _, call := range calls {
+		if err := call(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RetryPolicy describes how InTransactionRetry retries transactions.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first one.
+	// Zero or negative value means a single attempt.
+	MaxAttempts int
+
+	// Backoff is the base delay before the second attempt. It doubles for each next attempt,
+	// and actual delay is randomly chosen between a half and a whole of it.
+	Backoff time.Duration
+}
+
+// delay returns jittered delay before given attempt (2 for the first retry).
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	if p.Backoff <= 0 {
+		return 0
+	}
+
+	d := p.Backoff
+	for i := 2; i < attempt && d < time.Hour; i++ {
+		d *= 2
+	}
+	//nolint:gosec
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// isRetryable returns true if transaction failed with given error may succeed if retried:
+// it is a serialization failure or a deadlock, as classified by dialect (see ErrorClassifier).
+func (db *DB) isRetryable(err error) bool {
+	c, ok := db.Dialect.(ErrorClassifier)
+	if !ok {
+		return false
+	}
+	kind, _ := c.ClassifyError(err)
+	return kind == SerializationFailure || kind == Deadlock
+}
+
+// InTransactionRetry wraps function execution in transaction with given context and options (can be nil)
+// like InTransactionContext, retrying the whole transaction according to given policy if it fails with
+// serialization failure or deadlock error (see ErrorClassifier). Functions added with AddOnCommitCall are called
+// only for committed attempt. If context is done while waiting before the next attempt, returned error
+// wraps both context error and the error of the last attempt.
+//
+// Each retry is logged as "RETRY" pseudo-query with the number of the next attempt and the maximum number
+// of attempts as arguments, the delay before it as duration, and the error of the previous attempt.
+func (db *DB) InTransactionRetry(ctx context.Context, opts *sql.TxOptions, policy RetryPolicy, f func(t *TX) error) error {
+	for attempt := 1; ; attempt++ {
+		onCommitCalls, err := db.inTransaction(ctx, opts, f)
+		if err == nil {
+			return runOnCommitCalls(onCommitCalls)
+		}
+		if attempt >= policy.MaxAttempts || !db.isRetryable(err) {
+			return err
+		}
+
+		args := []interface{}{attempt + 1, policy.MaxAttempts}
+		db.logBefore("RETRY", args)
+		d := policy.delay(attempt + 1)
+		timer := time.NewTimer(d)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			db.logAfter("RETRY", args, d, err)
+			return fmt.Errorf("%w (last attempt: %w)", ctx.Err(), err)
 		}
+		db.logAfter("RETRY", args, d, err)
 	}
-	return err
 }
 
 // MasterQuerier returns Querier that uses only master connection.
 func (db *DB) MasterQuerier() *Querier {
 	q := db.clone()
 	q.inTransaction = false
-	q.slaves = nil
+	q.replicas = nil
 	q.onCommitCalls = nil
 
 	return q
@@ -1,9 +1,12 @@
 package reform_test
 
 import (
+	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/AlekSi/pointer"
 	"github.com/brianvoe/gofakeit"
@@ -171,3 +174,343 @@ func TestInTransaction(t *testing.T) {
 	assert.NoError(t, db.Reload(person))
 	assert.NoError(t, db.Delete(person))
 }
+
+func TestTXInTransaction(t *testing.T) {
+	db := setupDB(t)
+	defer teardown(t, db)
+
+	person1 := &Person{ID: 42, Email: pointer.ToString(gofakeit.Email())}
+	person2 := &Person{ID: 43, Email: pointer.ToString(gofakeit.Email())}
+	person3 := &Person{ID: 44, Email: pointer.ToString(gofakeit.Email())}
+	defer func() {
+		_, err := db.DeleteFrom(PersonTable, "WHERE id IN ("+db.Placeholder(1)+", "+db.Placeholder(2)+", "+db.Placeholder(3)+")", 42, 43, 44)
+		require.NoError(t, err)
+	}()
+
+	var calls []string
+	err := db.InTransaction(func(tx *reform.TX) error {
+		require.NoError(t, insertPersonWithID(t, tx.Querier, person1))
+		tx.AddOnCommitCall(func() error { calls = append(calls, "outer"); return nil })
+
+		// error in nested closure
+		err := tx.InTransaction(func(tx *reform.TX) error {
+			require.NoError(t, insertPersonWithID(t, tx.Querier, person2))
+			tx.AddOnCommitCall(func() error { calls = append(calls, "error"); return nil })
+			return errors.New("epic error")
+		})
+		assert.EqualError(t, err, "epic error")
+		assert.Equal(t, reform.ErrNoRows, tx.Reload(person2))
+
+		// panic in nested closure
+		assert.Panics(t, func() {
+			_ = tx.InTransaction(func(tx *reform.TX) error {
+				require.NoError(t, insertPersonWithID(t, tx.Querier, person2))
+				panic("epic panic!")
+			})
+		})
+		assert.Equal(t, reform.ErrNoRows, tx.Reload(person2))
+
+		// duplicate PK in nested closure does not abort transaction
+		err = tx.InTransaction(func(tx *reform.TX) error {
+			return insertPersonWithID(t, tx.Querier, person1)
+		})
+		_, ok := reform.IsUniqueViolation(err)
+		assert.True(t, ok, "%+v", err)
+
+		// no error in doubly nested closures
+		err = tx.InTransaction(func(tx *reform.TX) error {
+			require.NoError(t, insertPersonWithID(t, tx.Querier, person2))
+			tx.AddOnCommitCall(func() error { calls = append(calls, "nested"); return nil })
+			return tx.InTransaction(func(tx *reform.TX) error {
+				return insertPersonWithID(t, tx.Querier, person3)
+			})
+		})
+		assert.NoError(t, err)
+
+		assert.Empty(t, calls)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"outer", "nested"}, calls)
+
+	for _, p := range []*Person{person1, person2, person3} {
+		assert.NoError(t, db.Reload(p))
+	}
+}
+
+func TestSavepoint(t *testing.T) {
+	db, tx := setupTX(t)
+	defer teardown(t, db)
+	defer func() {
+		require.NoError(t, tx.Rollback())
+	}()
+
+	person := &Person{ID: 42, Email: pointer.ToString(gofakeit.Email())}
+
+	require.NoError(t, tx.Savepoint("sp1"))
+	require.NoError(t, insertPersonWithID(t, tx.Querier, person))
+	require.NoError(t, tx.RollbackTo("sp1"))
+	assert.Equal(t, reform.ErrNoRows, tx.Reload(person))
+
+	// savepoint stays active after rollback
+	require.NoError(t, insertPersonWithID(t, tx.Querier, person))
+	require.NoError(t, tx.RollbackTo("sp1"))
+	assert.Equal(t, reform.ErrNoRows, tx.Reload(person))
+	require.NoError(t, tx.Release("sp1"))
+
+	require.NoError(t, tx.Savepoint("sp2"))
+	require.NoError(t, insertPersonWithID(t, tx.Querier, person))
+	require.NoError(t, tx.Release("sp2"))
+	assert.NoError(t, tx.Reload(person))
+}
+
+// errSerialization is classified as serialization failure by retryDialect.
+var errSerialization = errors.New("serialization failure")
+
+// retryDialect classifies errSerialization as serialization failure.
+type retryDialect struct {
+	reform.Dialect
+}
+
+func (retryDialect) ClassifyError(err error) (reform.ErrorKind, string) {
+	if errors.Is(err, errSerialization) {
+		return reform.SerializationFailure, ""
+	}
+	return reform.OtherError, ""
+}
+
+// retryLogger records RETRY pseudo-queries.
+type retryLogger struct {
+	reform.Logger
+	retries [][]interface{}
+}
+
+func (l *retryLogger) After(query string, args []interface{}, d time.Duration, err error) {
+	if query == "RETRY" {
+		l.retries = append(l.retries, args)
+	}
+	l.Logger.After(query, args, d, err)
+}
+
+func TestInTransactionRetry(t *testing.T) {
+	db := setupDB(t)
+	defer teardown(t, db)
+
+	logger := &retryLogger{Logger: db.Logger}
+	db.Logger = logger
+	db.Querier.Dialect = retryDialect{Dialect: db.Dialect}
+
+	ctx := context.Background()
+	policy := reform.RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}
+	person := &Person{ID: 42, Email: pointer.ToString(gofakeit.Email())}
+
+	t.Run("Success", func(t *testing.T) {
+		logger.retries = nil
+		defer func() {
+			require.NoError(t, db.Delete(person))
+		}()
+
+		var attempts int
+		var calls []int
+		err := db.InTransactionRetry(ctx, nil, policy, func(tx *reform.TX) error {
+			attempts++
+			a := attempts
+			tx.AddOnCommitCall(func() error { calls = append(calls, a); return nil })
+
+			// fails if previous attempt was not rolled back
+			require.NoError(t, insertPersonWithID(t, tx.Querier, person))
+			if attempts < 3 {
+				return fmt.Errorf("attempt %d: %w", attempts, errSerialization)
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 3, attempts)
+		assert.Equal(t, []int{3}, calls)
+		assert.Equal(t, [][]interface{}{{2, 3}, {3, 3}}, logger.retries)
+		assert.NoError(t, db.Reload(person))
+	})
+
+	t.Run("MaxAttempts", func(t *testing.T) {
+		logger.retries = nil
+
+		var attempts int
+		err := db.InTransactionRetry(ctx, nil, policy, func(tx *reform.TX) error {
+			attempts++
+			require.NoError(t, insertPersonWithID(t, tx.Querier, person))
+			return errSerialization
+		})
+		assert.Equal(t, errSerialization, err)
+		assert.Equal(t, 3, attempts)
+		assert.Len(t, logger.retries, 2)
+		assert.Equal(t, reform.ErrNoRows, db.Reload(person))
+	})
+
+	t.Run("NotRetryable", func(t *testing.T) {
+		logger.retries = nil
+
+		var attempts int
+		err := db.InTransactionRetry(ctx, nil, policy, func(tx *reform.TX) error {
+			attempts++
+			return errors.New("epic error")
+		})
+		assert.EqualError(t, err, "epic error")
+		assert.Equal(t, 1, attempts)
+		assert.Empty(t, logger.retries)
+
+		attempts = 0
+		err = db.InTransactionRetry(ctx, nil, reform.RetryPolicy{}, func(tx *reform.TX) error {
+			attempts++
+			return errSerialization
+		})
+		assert.Equal(t, errSerialization, err)
+		assert.Equal(t, 1, attempts)
+	})
+
+	t.Run("Context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		var attempts int
+		err := db.InTransactionRetry(ctx, nil, reform.RetryPolicy{MaxAttempts: 3, Backoff: time.Hour}, func(tx *reform.TX) error {
+			attempts++
+			cancel()
+			return errSerialization
+		})
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.ErrorIs(t, err, errSerialization)
+		assert.Equal(t, 1, attempts)
+	})
+}
+
+func TestAutoTimestamps(t *testing.T) {
+	db := setupDB(t)
+	defer teardown(t, db)
+
+	now := time.Date(2020, 1, 2, 3, 4, 5, 6, time.FixedZone("UTC+3", 3*60*60))
+	expected := time.Date(2020, 1, 2, 0, 4, 5, 0, time.UTC)
+	db.SetClock(func() time.Time { return now })
+	db.SetTimePrecision(time.Second)
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, tx.Rollback())
+	}()
+
+	t.Run("Insert", func(t *testing.T) {
+		doc := &Document{Name: "insert"}
+		require.NoError(t, tx.Insert(doc))
+		assert.Equal(t, expected, doc.CreatedAt)
+		assert.Nil(t, doc.UpdatedAt)
+
+		created := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+		doc = &Document{Name: "insert", CreatedAt: created}
+		require.NoError(t, tx.Insert(doc))
+		assert.Equal(t, created, doc.CreatedAt)
+	})
+
+	t.Run("InsertColumns", func(t *testing.T) {
+		doc := &Document{Name: "insert columns"}
+		require.NoError(t, tx.InsertColumns(doc, "name", "lock_version"))
+		assert.Equal(t, expected, doc.CreatedAt)
+
+		doc2, err := tx.FindByPrimaryKeyFrom(DocumentTable, doc.ID)
+		require.NoError(t, err)
+		assert.Equal(t, expected, doc2.(*Document).CreatedAt.UTC())
+	})
+
+	t.Run("InsertMulti", func(t *testing.T) {
+		doc1, doc2 := &Document{Name: "insert multi"}, &Document{Name: "insert multi"}
+		require.NoError(t, tx.InsertMulti(doc1, doc2))
+		assert.Equal(t, expected, doc1.CreatedAt)
+		assert.Equal(t, expected, doc2.CreatedAt)
+	})
+
+	t.Run("Upsert", func(t *testing.T) {
+		doc := &Document{Name: "upsert"}
+		require.NoError(t, tx.Insert(doc))
+
+		doc2 := &Document{ID: doc.ID, Name: "upserted", CreatedAt: expected.Add(time.Hour)}
+		withIdentityInsert(t, tx.Querier, "documents", func() {
+			require.NoError(t, tx.Upsert(doc2, []string{"id"}))
+		})
+
+		require.NoError(t, tx.Reload(doc))
+		assert.Equal(t, "upserted", doc.Name)
+		assert.Equal(t, expected, doc.CreatedAt.UTC())
+		require.NotNil(t, doc.UpdatedAt, "autoupdate column is set for updated row")
+		assert.Equal(t, expected, doc.UpdatedAt.UTC())
+
+		// with explicit update columns too
+		now = now.Add(time.Hour)
+		defer func() { now = now.Add(-time.Hour) }()
+		withIdentityInsert(t, tx.Querier, "documents", func() {
+			require.NoError(t, tx.Upsert(doc2, []string{"id"}, "name"))
+		})
+		require.NoError(t, tx.Reload(doc))
+		require.NotNil(t, doc.UpdatedAt)
+		assert.Equal(t, expected.Add(time.Hour), doc.UpdatedAt.UTC())
+
+		// but not for inserted row
+		doc3 := &Document{ID: doc.ID + 1000, Name: "upsert inserted"}
+		withIdentityInsert(t, tx.Querier, "documents", func() {
+			require.NoError(t, tx.Upsert(doc3, []string{"id"}))
+		})
+		require.NoError(t, tx.Reload(doc3))
+		assert.Nil(t, doc3.UpdatedAt)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		doc := &Document{Name: "update"}
+		require.NoError(t, tx.Insert(doc))
+
+		created := doc.CreatedAt
+		now = now.Add(time.Hour)
+		defer func() { now = now.Add(-time.Hour) }()
+
+		require.NoError(t, tx.Update(doc))
+		assert.Equal(t, created, doc.CreatedAt)
+		assert.Equal(t, pointer.ToTime(expected.Add(time.Hour)), doc.UpdatedAt)
+
+		// autocreate column is never updated
+		doc2 := &Document{ID: doc.ID, Name: "updated", LockVersion: doc.LockVersion}
+		require.NoError(t, tx.Update(doc2))
+		assert.True(t, doc2.CreatedAt.IsZero())
+		require.NoError(t, tx.Reload(doc2))
+		assert.Equal(t, created, doc2.CreatedAt.UTC())
+	})
+
+	t.Run("UpdateColumns", func(t *testing.T) {
+		doc := &Document{Name: "update columns"}
+		require.NoError(t, tx.Insert(doc))
+
+		doc.Name = "updated columns"
+		require.NoError(t, tx.UpdateColumns(doc, "name"))
+		assert.Equal(t, pointer.ToTime(expected), doc.UpdatedAt)
+
+		doc2, err := tx.FindByPrimaryKeyFrom(DocumentTable, doc.ID)
+		require.NoError(t, err)
+		require.NotNil(t, doc2.(*Document).UpdatedAt)
+		assert.Equal(t, expected, doc2.(*Document).UpdatedAt.UTC())
+	})
+
+	t.Run("UpdateView", func(t *testing.T) {
+		doc := &Document{Name: "update view"}
+		require.NoError(t, tx.Insert(doc))
+
+		ra, err := tx.UpdateView(&Document{Name: "updated view"}, []string{"name"}, "WHERE id = "+tx.Placeholder(1), doc.ID)
+		require.NoError(t, err)
+		assert.Equal(t, uint(1), ra)
+
+		require.NoError(t, tx.Reload(doc))
+		require.NotNil(t, doc.UpdatedAt)
+		assert.Equal(t, expected, doc.UpdatedAt.UTC())
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		doc := &Document{Name: "delete"}
+		require.NoError(t, tx.Insert(doc))
+
+		require.NoError(t, tx.Delete(doc))
+		assert.Equal(t, pointer.ToTime(expected), doc.DeletedAt)
+	})
+}
@@ -4,7 +4,11 @@
 package mssql
 
 import (
+	"context"
+	"time"
+
 	"github.com/mc2soft/reform"
+	"github.com/mc2soft/reform/dialects/sqlserver"
 )
 
 type mssql struct{}
@@ -33,6 +37,10 @@ func (mssql) LastInsertIdMethod() reform.LastInsertIdMethod {
 	return reform.OutputInserted
 }
 
+func (mssql) LastInsertIdMultiMethod() reform.LastInsertIdMultiMethod {
+	return reform.NoLastInsertIdMulti
+}
+
 func (mssql) SelectLimitMethod() reform.SelectLimitMethod {
 	return reform.SelectTop
 }
@@ -41,10 +49,37 @@ func (mssql) DefaultValuesMethod() reform.DefaultValuesMethod {
 	return reform.DefaultValues
 }
 
+func (mssql) UpsertMethod() reform.UpsertMethod {
+	return reform.Merge
+}
+
+func (mssql) SavepointMethod() reform.SavepointMethod {
+	return reform.SaveTransaction
+}
+
+func (mssql) MaxPlaceholders() int {
+	// 2100 parameters minus two used by sp_executesql
+	return 2098
+}
+
+// ClassifyError classifies denisenkom/go-mssqldb errors the same way as sqlserver dialect.
+func (mssql) ClassifyError(err error) (reform.ErrorKind, string) {
+	return sqlserver.Dialect.ClassifyError(err)
+}
+
+// ReplicationLag returns replication lag the same way as sqlserver dialect.
+func (mssql) ReplicationLag(ctx context.Context, db reform.DBTXContext) (time.Duration, error) {
+	return sqlserver.Dialect.ReplicationLag(ctx, db)
+}
+
 // Dialect implements reform.Dialect for Microsoft SQL Server.
 //
 // Deprecated: Use sqlserver.Dialect instead. https://github.com/denisenkom/go-mssqldb#deprecated
 var Dialect mssql
 
-// check interface
-var _ reform.Dialect = Dialect
+// check interfaces
+var (
+	_ reform.Dialect              = Dialect
+	_ reform.ErrorClassifier      = Dialect
+	_ reform.ReplicationLagProber = Dialect
+)
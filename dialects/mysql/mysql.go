@@ -1,7 +1,17 @@
 // Package mysql implements reform.Dialect for MySQL.
 package mysql
 
-import "github.com/mc2soft/reform"
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mc2soft/reform"
+)
 
 type mysql struct{}
 
@@ -29,6 +39,10 @@ func (mysql) LastInsertIdMethod() reform.LastInsertIdMethod {
 	return reform.LastInsertId
 }
 
+func (mysql) LastInsertIdMultiMethod() reform.LastInsertIdMultiMethod {
+	return reform.FirstInsertId
+}
+
 func (mysql) SelectLimitMethod() reform.SelectLimitMethod {
 	return reform.Limit
 }
@@ -37,8 +51,127 @@ func (mysql) DefaultValuesMethod() reform.DefaultValuesMethod {
 	return reform.EmptyLists
 }
 
+func (mysql) UpsertMethod() reform.UpsertMethod {
+	return reform.OnDuplicateKeyUpdate
+}
+
+func (mysql) SavepointMethod() reform.SavepointMethod {
+	return reform.Savepoint
+}
+
+func (mysql) MaxPlaceholders() int {
+	// MySQL wire protocol uses uint16 for the number of prepared statement parameters.
+	return 65535
+}
+
+// mysqlError returns number and message of go-sql-driver/mysql error (MySQLError) in err's chain.
+// That error has no methods exposing them, so fields are accessed via reflection
+// to avoid importing the driver.
+func mysqlError(err error) (number uint16, message string, ok bool) {
+	for ; err != nil; err = errors.Unwrap(err) {
+		v := reflect.Indirect(reflect.ValueOf(err))
+		if v.Kind() != reflect.Struct {
+			continue
+		}
+		n, m := v.FieldByName("Number"), v.FieldByName("Message")
+		if n.Kind() == reflect.Uint16 && m.Kind() == reflect.String {
+			return uint16(n.Uint()), m.String(), true
+		}
+	}
+	return 0, "", false
+}
+
+// ClassifyError classifies go-sql-driver/mysql errors by their numbers.
+// Constraint and column names are extracted from error messages.
+func (mysql) ClassifyError(err error) (reform.ErrorKind, string) {
+	number, msg, ok := mysqlError(err)
+	if !ok {
+		return reform.OtherError, ""
+	}
+
+	switch number {
+	case 1062: // ER_DUP_ENTRY: Duplicate entry '%s' for key '%s'
+		i := strings.LastIndex(msg, " for key '")
+		if i < 0 {
+			return reform.UniqueViolation, ""
+		}
+		return reform.UniqueViolation, strings.TrimSuffix(msg[i+len(" for key '"):], "'")
+	case 1451, 1452: // ER_ROW_IS_REFERENCED_2, ER_NO_REFERENCED_ROW_2
+		_, s, _ := strings.Cut(msg, "CONSTRAINT `")
+		constraint, _, _ := strings.Cut(s, "`")
+		return reform.ForeignKeyViolation, constraint
+	case 1048: // ER_BAD_NULL_ERROR: Column '%s' cannot be null
+		_, s, _ := strings.Cut(msg, "Column '")
+		column, _, _ := strings.Cut(s, "'")
+		return reform.NotNullViolation, column
+	case 1213: // ER_LOCK_DEADLOCK
+		return reform.Deadlock, ""
+	case 1205: // ER_LOCK_WAIT_TIMEOUT: Lock wait timeout exceeded; try restarting transaction
+		return reform.Deadlock, ""
+	default:
+		return reform.OtherError, ""
+	}
+}
+
+// ReplicationLag returns the maximal Seconds_Behind_Source (or Seconds_Behind_Master for older versions)
+// of all replication channels, or zero if it is not a replica.
+// It returns an error if replication is not running.
+func (mysql) ReplicationLag(ctx context.Context, db reform.DBTXContext) (time.Duration, error) {
+	rows, err := db.QueryContext(ctx, "SHOW REPLICA STATUS")
+	if err != nil {
+		// MySQL before 8.0.22 and MariaDB before 10.5.1
+		if rows, err = db.QueryContext(ctx, "SHOW SLAVE STATUS"); err != nil {
+			return 0, err
+		}
+	}
+	defer rows.Close() //nolint:errcheck
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+	index := -1
+	for i, c := range columns {
+		if c == "Seconds_Behind_Source" || c == "Seconds_Behind_Master" {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return 0, errors.New("reform: replica status doesn't contain Seconds_Behind_Source column")
+	}
+
+	values := make([]sql.RawBytes, len(columns))
+	pointers := make([]interface{}, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+
+	var lag time.Duration
+	for rows.Next() {
+		if err = rows.Scan(pointers...); err != nil {
+			return 0, err
+		}
+		if values[index] == nil {
+			return 0, errors.New("reform: replication is not running")
+		}
+		var seconds int64
+		if seconds, err = strconv.ParseInt(string(values[index]), 10, 64); err != nil {
+			return 0, err
+		}
+		if d := time.Duration(seconds) * time.Second; d > lag {
+			lag = d
+		}
+	}
+	return lag, rows.Err()
+}
+
 // Dialect implements reform.Dialect for MySQL.
 var Dialect mysql
 
-// check interface
-var _ reform.Dialect = Dialect
+// check interfaces
+var (
+	_ reform.Dialect              = Dialect
+	_ reform.ErrorClassifier      = Dialect
+	_ reform.ReplicationLagProber = Dialect
+)
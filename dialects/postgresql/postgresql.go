@@ -2,7 +2,11 @@
 package postgresql
 
 import (
+	"context"
+	"errors"
+	"reflect"
 	"strconv"
+	"time"
 
 	"github.com/mc2soft/reform"
 )
@@ -33,6 +37,10 @@ func (postgresql) LastInsertIdMethod() reform.LastInsertIdMethod {
 	return reform.Returning
 }
 
+func (postgresql) LastInsertIdMultiMethod() reform.LastInsertIdMultiMethod {
+	return reform.NoLastInsertIdMulti
+}
+
 func (postgresql) SelectLimitMethod() reform.SelectLimitMethod {
 	return reform.Limit
 }
@@ -41,8 +49,94 @@ func (postgresql) DefaultValuesMethod() reform.DefaultValuesMethod {
 	return reform.DefaultValues
 }
 
+func (postgresql) UpsertMethod() reform.UpsertMethod {
+	return reform.OnConflict
+}
+
+func (postgresql) SavepointMethod() reform.SavepointMethod {
+	return reform.Savepoint
+}
+
+func (postgresql) MaxPlaceholders() int {
+	// PostgreSQL wire protocol uses uint16 for the number of parameters.
+	return 65535
+}
+
+// pqError is implemented by lib/pq errors.
+type pqError interface {
+	error
+	Get(k byte) string
+}
+
+// sqlStateError is implemented by jackc/pgx errors (v3's PgError, and v4's and v5's pgconn.PgError).
+type sqlStateError interface {
+	error
+	SQLState() string
+}
+
+// stringField returns a value of string field with given name of error struct (possibly behind pointer),
+// or empty string.
+func stringField(err error, name string) string {
+	v := reflect.Indirect(reflect.ValueOf(err))
+	if v.Kind() != reflect.Struct {
+		return ""
+	}
+	if f := v.FieldByName(name); f.Kind() == reflect.String {
+		return f.String()
+	}
+	return ""
+}
+
+// ClassifyError classifies lib/pq and jackc/pgx errors by their SQLSTATE codes.
+func (postgresql) ClassifyError(err error) (reform.ErrorKind, string) {
+	var code, constraint, column string
+	var pqErr pqError
+	var stateErr sqlStateError
+	switch {
+	case errors.As(err, &pqErr):
+		code, constraint, column = pqErr.Get('C'), pqErr.Get('n'), pqErr.Get('c')
+	case errors.As(err, &stateErr):
+		// pgx exposes constraint and column names only as struct fields
+		code = stateErr.SQLState()
+		constraint, column = stringField(stateErr, "ConstraintName"), stringField(stateErr, "ColumnName")
+	default:
+		return reform.OtherError, ""
+	}
+
+	switch code {
+	case "23505": // unique_violation
+		return reform.UniqueViolation, constraint
+	case "23503": // foreign_key_violation
+		return reform.ForeignKeyViolation, constraint
+	case "23502": // not_null_violation
+		return reform.NotNullViolation, column
+	case "40001": // serialization_failure
+		return reform.SerializationFailure, ""
+	case "40P01": // deadlock_detected
+		return reform.Deadlock, ""
+	default:
+		return reform.OtherError, ""
+	}
+}
+
+// ReplicationLag returns the time since the last replayed transaction, or zero if all received WAL is replayed
+// (so idle primary doesn't cause false lag) or if it is not a standby server.
+func (postgresql) ReplicationLag(ctx context.Context, db reform.DBTXContext) (time.Duration, error) {
+	const query = `SELECT COALESCE(CASE WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0 ` +
+		`ELSE EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()) END, 0)`
+	var seconds float64
+	if err := db.QueryRowContext(ctx, query).Scan(&seconds); err != nil {
+		return 0, err
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
 // Dialect implements reform.Dialect for PostgreSQL.
 var Dialect postgresql
 
-// check interface
-var _ reform.Dialect = Dialect
+// check interfaces
+var (
+	_ reform.Dialect              = Dialect
+	_ reform.ErrorClassifier      = Dialect
+	_ reform.ReplicationLagProber = Dialect
+)
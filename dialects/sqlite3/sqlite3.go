@@ -1,7 +1,12 @@
 // Package sqlite3 implements reform.Dialect for SQLite3.
 package sqlite3
 
-import "github.com/mc2soft/reform"
+import (
+	"errors"
+	"strings"
+
+	"github.com/mc2soft/reform"
+)
 
 type sqlite3 struct{}
 
@@ -29,6 +34,10 @@ func (sqlite3) LastInsertIdMethod() reform.LastInsertIdMethod {
 	return reform.LastInsertId
 }
 
+func (sqlite3) LastInsertIdMultiMethod() reform.LastInsertIdMultiMethod {
+	return reform.LastRowInsertId
+}
+
 func (sqlite3) SelectLimitMethod() reform.SelectLimitMethod {
 	return reform.Limit
 }
@@ -37,8 +46,46 @@ func (sqlite3) DefaultValuesMethod() reform.DefaultValuesMethod {
 	return reform.DefaultValues
 }
 
+func (sqlite3) UpsertMethod() reform.UpsertMethod {
+	return reform.OnConflict
+}
+
+func (sqlite3) SavepointMethod() reform.SavepointMethod {
+	return reform.Savepoint
+}
+
+func (sqlite3) MaxPlaceholders() int {
+	// default SQLITE_MAX_VARIABLE_NUMBER for SQLite3 versions before 3.32.0
+	return 999
+}
+
+// ClassifyError classifies SQLite3 errors by their messages, which are the same for all drivers.
+// Constraint name is not available; for UniqueViolation and NotNullViolation, "table.column"
+// (comma-separated for several columns) is returned instead.
+func (sqlite3) ClassifyError(err error) (reform.ErrorKind, string) {
+	// use the innermost error to get the driver's message without wrappers
+	for e := errors.Unwrap(err); e != nil; e = errors.Unwrap(e) {
+		err = e
+	}
+	msg := err.Error()
+
+	switch {
+	case strings.HasPrefix(msg, "UNIQUE constraint failed: "):
+		return reform.UniqueViolation, strings.TrimPrefix(msg, "UNIQUE constraint failed: ")
+	case strings.HasPrefix(msg, "NOT NULL constraint failed: "):
+		return reform.NotNullViolation, strings.TrimPrefix(msg, "NOT NULL constraint failed: ")
+	case strings.HasPrefix(msg, "FOREIGN KEY constraint failed"):
+		return reform.ForeignKeyViolation, ""
+	default:
+		return reform.OtherError, ""
+	}
+}
+
 // Dialect implements reform.Dialect for SQLite3.
 var Dialect sqlite3
 
-// check interface
-var _ reform.Dialect = Dialect
+// check interfaces
+var (
+	_ reform.Dialect         = Dialect
+	_ reform.ErrorClassifier = Dialect
+)
@@ -2,7 +2,11 @@
 package sqlserver
 
 import (
+	"context"
+	"errors"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/mc2soft/reform"
 )
@@ -33,6 +37,10 @@ func (sqlserver) LastInsertIdMethod() reform.LastInsertIdMethod {
 	return reform.OutputInserted
 }
 
+func (sqlserver) LastInsertIdMultiMethod() reform.LastInsertIdMultiMethod {
+	return reform.NoLastInsertIdMulti
+}
+
 func (sqlserver) SelectLimitMethod() reform.SelectLimitMethod {
 	return reform.SelectTop
 }
@@ -41,8 +49,89 @@ func (sqlserver) DefaultValuesMethod() reform.DefaultValuesMethod {
 	return reform.DefaultValues
 }
 
+func (sqlserver) UpsertMethod() reform.UpsertMethod {
+	return reform.Merge
+}
+
+func (sqlserver) SavepointMethod() reform.SavepointMethod {
+	return reform.SaveTransaction
+}
+
+func (sqlserver) MaxPlaceholders() int {
+	// 2100 parameters minus two used by sp_executesql
+	return 2098
+}
+
+// sqlError is implemented by denisenkom/go-mssqldb errors.
+type sqlError interface {
+	error
+	SQLErrorNumber() int32
+	SQLErrorMessage() string
+}
+
+// between returns a part of s between the first occurrence of prefix and the next occurrence of suffix,
+// or empty string.
+func between(s, prefix, suffix string) string {
+	i := strings.Index(s, prefix)
+	if i < 0 {
+		return ""
+	}
+	s = s[i+len(prefix):]
+	j := strings.Index(s, suffix)
+	if j < 0 {
+		return ""
+	}
+	return s[:j]
+}
+
+// ClassifyError classifies denisenkom/go-mssqldb errors by their numbers.
+// Constraint and column names are extracted from error messages.
+func (sqlserver) ClassifyError(err error) (reform.ErrorKind, string) {
+	var sqlErr sqlError
+	if !errors.As(err, &sqlErr) {
+		return reform.OtherError, ""
+	}
+
+	msg := sqlErr.SQLErrorMessage()
+	switch sqlErr.SQLErrorNumber() {
+	case 2627: // Violation of %ls constraint '%.*ls'. Cannot insert duplicate key in object '%.*ls'.
+		return reform.UniqueViolation, between(msg, "constraint '", "'")
+	case 2601: // Cannot insert duplicate key row in object '%.*ls' with unique index '%.*ls'.
+		return reform.UniqueViolation, between(msg, "unique index '", "'")
+	case 547: // The %ls statement conflicted with the %ls constraint "%.*ls".
+		if strings.Contains(msg, "FOREIGN KEY constraint") || strings.Contains(msg, "REFERENCE constraint") {
+			return reform.ForeignKeyViolation, between(msg, "constraint \"", "\"")
+		}
+		return reform.OtherError, ""
+	case 515: // Cannot insert the value NULL into column '%.*ls', table '%.*ls'; column does not allow nulls.
+		return reform.NotNullViolation, between(msg, "column '", "'")
+	case 3960: // Snapshot isolation transaction aborted due to update conflict.
+		return reform.SerializationFailure, ""
+	case 1205: // Transaction was deadlocked on resources with another process and has been chosen as the deadlock victim.
+		return reform.Deadlock, ""
+	default:
+		return reform.OtherError, ""
+	}
+}
+
+// ReplicationLag returns secondary lag of the current database in Always On availability group,
+// or zero if it is not a secondary replica.
+func (sqlserver) ReplicationLag(ctx context.Context, db reform.DBTXContext) (time.Duration, error) {
+	const query = `SELECT COALESCE(MAX(secondary_lag_seconds), 0) FROM sys.dm_hadr_database_replica_states ` +
+		`WHERE is_local = 1 AND database_id = DB_ID()`
+	var seconds int64
+	if err := db.QueryRowContext(ctx, query).Scan(&seconds); err != nil {
+		return 0, err
+	}
+	return time.Duration(seconds) * time.Second, nil
+}
+
 // Dialect implements reform.Dialect for Microsoft SQL Server.
 var Dialect sqlserver
 
-// check interface
-var _ reform.Dialect = Dialect
+// check interfaces
+var (
+	_ reform.Dialect              = Dialect
+	_ reform.ErrorClassifier      = Dialect
+	_ reform.ReplicationLagProber = Dialect
+)
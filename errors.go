@@ -0,0 +1,151 @@
+package reform
+
+import (
+	"errors"
+	"fmt"
+)
+
+// UnexpectedColumnsError is returned from various methods when given columns are not present in view.
+type UnexpectedColumnsError struct {
+	Columns []string
+}
+
+// Error returns error message.
+func (e *UnexpectedColumnsError) Error() string {
+	return fmt.Sprintf("reform: unexpected columns: %v", e.Columns)
+}
+
+// PKColumnUpdateError is returned from UpdateColumns, UpdateView, and Upsert-like methods
+// when primary key column is given as a column to update.
+type PKColumnUpdateError struct {
+	Column string
+}
+
+// Error returns error message.
+func (e *PKColumnUpdateError) Error() string {
+	return fmt.Sprintf("reform: will not update PK column: %s", e.Column)
+}
+
+// DifferentTablesError is returned from InsertMulti-like methods and Preload when given structs
+// belong to different views or tables.
+type DifferentTablesError struct {
+	Method string // method name, for example, "InsertMulti"
+	First  string // name of the first struct's view
+	Second string // name of the other struct's view
+}
+
+// Error returns error message.
+func (e *DifferentTablesError) Error() string {
+	return fmt.Sprintf("reform: different tables in %s: %s and %s", e.Method, e.First, e.Second)
+}
+
+// PKMismatchError is returned from InsertMulti-like methods when primary key is present in one record
+// and absent in other.
+type PKMismatchError struct {
+	First  Record
+	Second Record
+}
+
+// Error returns error message.
+func (e *PKMismatchError) Error() string {
+	return fmt.Sprintf("reform: PK in present in one struct and absent in other: first: %s, second: %s", e.First, e.Second)
+}
+
+// QueryError wraps an error returned by SQL database driver for a query made by Querier,
+// or for transaction's COMMIT or ROLLBACK.
+// Use errors.As or errors.Unwrap to get the original error.
+type QueryError struct {
+	Query string // query text, including tag
+	Tag   string // Querier's tag
+	Err   error  // original error
+
+	dialect Dialect
+}
+
+// Error returns error message.
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("%s (query: %s)", e.Err, e.Query)
+}
+
+// Unwrap returns the original error.
+func (e *QueryError) Unwrap() error {
+	return e.Err
+}
+
+// wrapError wraps non-nil err returned by SQL database driver for given query into QueryError.
+// ErrNoRows and QueryError (returned by slave's Querier) are returned as is.
+func (q *Querier) wrapError(query string, err error) error {
+	if err == nil || err == ErrNoRows {
+		return err
+	}
+	if _, ok := err.(*QueryError); ok {
+		return err
+	}
+	return &QueryError{
+		Query:   query,
+		Tag:     q.tag,
+		Err:     err,
+		dialect: q.Dialect,
+	}
+}
+
+// classifyError classifies QueryError with its Querier's dialect, if it implements ErrorClassifier.
+func classifyError(err error) (ErrorKind, string) {
+	var queryErr *QueryError
+	if !errors.As(err, &queryErr) {
+		return OtherError, ""
+	}
+	c, ok := queryErr.dialect.(ErrorClassifier)
+	if !ok {
+		return OtherError, ""
+	}
+	return c.ClassifyError(queryErr.Err)
+}
+
+// IsUniqueViolation returns true if err returned by Querier's method (see QueryError) is caused
+// by violation of unique or primary key constraint, and the name of that constraint if it is available.
+// For other errors, use Dialect's ClassifyError method directly (see ErrorClassifier).
+func IsUniqueViolation(err error) (constraint string, ok bool) {
+	kind, constraint := classifyError(err)
+	return constraint, kind == UniqueViolation
+}
+
+// IsForeignKeyViolation returns true if err returned by Querier's method (see QueryError) is caused
+// by violation of foreign key constraint, and the name of that constraint if it is available.
+// For other errors, use Dialect's ClassifyError method directly (see ErrorClassifier).
+func IsForeignKeyViolation(err error) (constraint string, ok bool) {
+	kind, constraint := classifyError(err)
+	return constraint, kind == ForeignKeyViolation
+}
+
+// IsNotNullViolation returns true if err returned by Querier's method (see QueryError) is caused
+// by violation of NOT NULL constraint, and the name of column if it is available.
+// For other errors, use Dialect's ClassifyError method directly (see ErrorClassifier).
+func IsNotNullViolation(err error) (column string, ok bool) {
+	kind, column := classifyError(err)
+	return column, kind == NotNullViolation
+}
+
+// IsSerializationFailure returns true if err returned by Querier's method (see QueryError) is caused
+// by concurrent update conflict in transaction.
+// For other errors, use Dialect's ClassifyError method directly (see ErrorClassifier).
+func IsSerializationFailure(err error) bool {
+	kind, _ := classifyError(err)
+	return kind == SerializationFailure
+}
+
+// IsDeadlock returns true if err returned by Querier's method (see QueryError) is caused by detected deadlock.
+// For other errors, use Dialect's ClassifyError method directly (see ErrorClassifier).
+func IsDeadlock(err error) bool {
+	kind, _ := classifyError(err)
+	return kind == Deadlock
+}
+
+// check interfaces
+var (
+	_ error = (*UnexpectedColumnsError)(nil)
+	_ error = (*PKColumnUpdateError)(nil)
+	_ error = (*DifferentTablesError)(nil)
+	_ error = (*PKMismatchError)(nil)
+	_ error = (*QueryError)(nil)
+)
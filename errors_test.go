@@ -0,0 +1,248 @@
+package reform_test
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	mssqlDriver "github.com/denisenkom/go-mssqldb"
+	mysqlDriver "github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mc2soft/reform"
+	"github.com/mc2soft/reform/dialects/mssql" //nolint:staticcheck
+	"github.com/mc2soft/reform/dialects/mysql"
+	"github.com/mc2soft/reform/dialects/postgresql"
+	"github.com/mc2soft/reform/dialects/sqlite3"
+	"github.com/mc2soft/reform/dialects/sqlserver"
+	. "github.com/mc2soft/reform/internal/test/models"
+)
+
+func TestErrors(t *testing.T) {
+	db, tx := setupTX(t)
+	defer teardown(t, db)
+	defer func() {
+		require.NoError(t, tx.Rollback())
+	}()
+
+	t.Run("UnexpectedColumns", func(t *testing.T) {
+		person := &Person{ID: 102, Name: "Elfrieda Abbott"}
+		err := tx.UpdateColumns(person, "name", "foo", "bar")
+		var columnsErr *reform.UnexpectedColumnsError
+		require.True(t, errors.As(err, &columnsErr))
+		assert.Equal(t, []string{"bar", "foo"}, columnsErr.Columns)
+		assert.EqualError(t, err, "reform: unexpected columns: [bar foo]")
+
+		assert.True(t, errors.Is(tx.UpdateColumns(person), reform.ErrNothingToUpdate))
+	})
+
+	t.Run("PKColumnUpdate", func(t *testing.T) {
+		person := &Person{ID: 102, Name: "Elfrieda Abbott"}
+		err := tx.UpdateColumns(person, "name", "id")
+		var pkErr *reform.PKColumnUpdateError
+		require.True(t, errors.As(err, &pkErr))
+		assert.Equal(t, "id", pkErr.Column)
+		assert.EqualError(t, err, "reform: will not update PK column: id")
+
+		err = tx.Upsert(&Project{ID: "new", Name: "New"}, nil)
+		assert.True(t, errors.Is(err, reform.ErrNoConflictColumns))
+	})
+
+	t.Run("DifferentTables", func(t *testing.T) {
+		err := tx.InsertMulti(&Person{Name: "New"}, &Project{ID: "new", Name: "New"})
+		var tablesErr *reform.DifferentTablesError
+		require.True(t, errors.As(err, &tablesErr))
+		assert.Equal(t, &reform.DifferentTablesError{Method: "InsertMulti", First: "people", Second: "projects"}, tablesErr)
+	})
+
+	t.Run("PKMismatch", func(t *testing.T) {
+		person1 := &Person{Name: "New 1"}
+		person2 := &Person{ID: 1000, Name: "New 2"}
+		err := tx.InsertMulti(person1, person2)
+		var pkErr *reform.PKMismatchError
+		require.True(t, errors.As(err, &pkErr))
+		assert.Equal(t, person1, pkErr.First)
+		assert.Equal(t, person2, pkErr.Second)
+	})
+
+	t.Run("Query", func(t *testing.T) {
+		q := tx.WithTag("test:errors")
+		_, err := q.Exec("SELECT * FROM no_such_table")
+		var queryErr *reform.QueryError
+		require.True(t, errors.As(err, &queryErr))
+		assert.Equal(t, "test:errors", queryErr.Tag)
+		assert.Equal(t, "SELECT * FROM no_such_table", queryErr.Query)
+		require.Error(t, queryErr.Err)
+		assert.Equal(t, queryErr.Err, errors.Unwrap(err))
+		assert.Contains(t, err.Error(), queryErr.Err.Error())
+
+		_, err = q.Query("SELECT * FROM no_such_table")
+		assert.True(t, errors.As(err, &queryErr))
+
+		err = q.SelectOneTo(new(Person), "WHERE no_such_column = 1")
+		require.True(t, errors.As(err, &queryErr))
+		assert.Contains(t, queryErr.Query, "/* test:errors */")
+
+		// ErrNoRows is not wrapped
+		err = q.FindByPrimaryKeyTo(new(Person), -1)
+		assert.Equal(t, reform.ErrNoRows, err)
+	})
+}
+
+// commitErrorTX is TXInterface which Commit returns errSerialization without committing.
+type commitErrorTX struct {
+	reform.TXInterface
+}
+
+func (commitErrorTX) Commit() error {
+	return errSerialization
+}
+
+func TestErrorsCommit(t *testing.T) {
+	db := setupDB(t)
+	defer teardown(t, db)
+
+	sqlTX, err := db.DBInterface().(*sql.DB).Begin()
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, sqlTX.Rollback())
+	}()
+
+	tx := reform.NewTXFromInterface(commitErrorTX{TXInterface: sqlTX}, retryDialect{Dialect: db.Dialect}, nil)
+	err = tx.Commit()
+	var queryErr *reform.QueryError
+	require.True(t, errors.As(err, &queryErr))
+	assert.Equal(t, "COMMIT", queryErr.Query)
+	assert.Equal(t, errSerialization, queryErr.Err)
+	assert.True(t, reform.IsSerializationFailure(err))
+	assert.False(t, reform.IsDeadlock(err))
+}
+
+// pgconnError mimics jackc/pgx v4's and v5's pgconn.PgError.
+type pgconnError struct {
+	Code           string
+	ColumnName     string
+	ConstraintName string
+}
+
+func (e *pgconnError) Error() string {
+	return "ERROR (SQLSTATE " + e.Code + ")"
+}
+
+func (e *pgconnError) SQLState() string {
+	return e.Code
+}
+
+func TestClassifyError(t *testing.T) {
+	t.Run("Dialects", func(t *testing.T) {
+		type expected struct {
+			kind       reform.ErrorKind
+			constraint string
+		}
+		for _, tc := range []struct {
+			dialect  reform.Dialect
+			err      error
+			expected expected
+		}{
+			{postgresql.Dialect, &pq.Error{Code: "23505", Constraint: "people_pkey"}, expected{reform.UniqueViolation, "people_pkey"}},
+			{postgresql.Dialect, &pq.Error{Code: "23503", Constraint: "person_project_person_id_fkey"}, expected{reform.ForeignKeyViolation, "person_project_person_id_fkey"}},
+			{postgresql.Dialect, &pq.Error{Code: "23502", Column: "name"}, expected{reform.NotNullViolation, "name"}},
+			{postgresql.Dialect, &pq.Error{Code: "40001"}, expected{reform.SerializationFailure, ""}},
+			{postgresql.Dialect, &pq.Error{Code: "40P01"}, expected{reform.Deadlock, ""}},
+			{postgresql.Dialect, &pq.Error{Code: "42P01"}, expected{reform.OtherError, ""}},
+			{postgresql.Dialect, pgx.PgError{Code: "23505", ConstraintName: "people_pkey"}, expected{reform.UniqueViolation, "people_pkey"}},
+			{postgresql.Dialect, &pgx.PgError{Code: "23502", ColumnName: "name"}, expected{reform.NotNullViolation, "name"}},
+			{postgresql.Dialect, &pgconnError{Code: "23503", ConstraintName: "person_project_person_id_fkey"}, expected{reform.ForeignKeyViolation, "person_project_person_id_fkey"}},
+			{postgresql.Dialect, &pgconnError{Code: "40001"}, expected{reform.SerializationFailure, ""}},
+
+			{mysql.Dialect, &mysqlDriver.MySQLError{
+				Number:  1062,
+				Message: "Duplicate entry '1' for key 'PRIMARY'",
+			}, expected{reform.UniqueViolation, "PRIMARY"}},
+			{mysql.Dialect, &mysqlDriver.MySQLError{
+				Number:  1452,
+				Message: "Cannot add or update a child row: a foreign key constraint fails (`reform-database`.`person_project`, CONSTRAINT `person_project_ibfk_1` FOREIGN KEY (`person_id`) REFERENCES `people` (`id`) ON DELETE CASCADE)",
+			}, expected{reform.ForeignKeyViolation, "person_project_ibfk_1"}},
+			{mysql.Dialect, &mysqlDriver.MySQLError{
+				Number:  1048,
+				Message: "Column 'name' cannot be null",
+			}, expected{reform.NotNullViolation, "name"}},
+			{mysql.Dialect, &mysqlDriver.MySQLError{Number: 1213}, expected{reform.Deadlock, ""}},
+			{mysql.Dialect, &mysqlDriver.MySQLError{Number: 1205}, expected{reform.Deadlock, ""}},
+
+			{sqlserver.Dialect, mssqlDriver.Error{
+				Number:  2627,
+				Message: "Violation of PRIMARY KEY constraint 'PK__people__3213E83F'. Cannot insert duplicate key in object 'dbo.people'. The duplicate key value is (1).",
+			}, expected{reform.UniqueViolation, "PK__people__3213E83F"}},
+			{sqlserver.Dialect, mssqlDriver.Error{
+				Number:  2601,
+				Message: "Cannot insert duplicate key row in object 'dbo.person_project' with unique index 'person_project_idx'. The duplicate key value is (1, baron).",
+			}, expected{reform.UniqueViolation, "person_project_idx"}},
+			{mssql.Dialect, mssqlDriver.Error{ //nolint:staticcheck
+				Number:  547,
+				Message: `The INSERT statement conflicted with the FOREIGN KEY constraint "FK__person_pr__perso__412EB0B6". The conflict occurred in database "reform-database", table "dbo.people", column 'id'.`,
+			}, expected{reform.ForeignKeyViolation, "FK__person_pr__perso__412EB0B6"}},
+			{sqlserver.Dialect, mssqlDriver.Error{
+				Number:  547,
+				Message: `The INSERT statement conflicted with the CHECK constraint "CK_people_name".`,
+			}, expected{reform.OtherError, ""}},
+			{sqlserver.Dialect, mssqlDriver.Error{
+				Number:  515,
+				Message: "Cannot insert the value NULL into column 'name', table 'reform-database.dbo.people'; column does not allow nulls. INSERT fails.",
+			}, expected{reform.NotNullViolation, "name"}},
+			{sqlserver.Dialect, mssqlDriver.Error{Number: 3960}, expected{reform.SerializationFailure, ""}},
+			{sqlserver.Dialect, mssqlDriver.Error{Number: 1205}, expected{reform.Deadlock, ""}},
+
+			{sqlite3.Dialect, errors.New("UNIQUE constraint failed: people.email"), expected{reform.UniqueViolation, "people.email"}},
+			{sqlite3.Dialect, errors.New("NOT NULL constraint failed: people.name"), expected{reform.NotNullViolation, "people.name"}},
+			{sqlite3.Dialect, errors.New("FOREIGN KEY constraint failed"), expected{reform.ForeignKeyViolation, ""}},
+
+			{postgresql.Dialect, errors.New("epic error"), expected{reform.OtherError, ""}},
+			{mysql.Dialect, errors.New("epic error"), expected{reform.OtherError, ""}},
+			{sqlserver.Dialect, errors.New("epic error"), expected{reform.OtherError, ""}},
+			{sqlite3.Dialect, errors.New("epic error"), expected{reform.OtherError, ""}},
+		} {
+			for _, err := range []error{tc.err, fmt.Errorf("wrapped: %w", tc.err)} {
+				kind, constraint := tc.dialect.(reform.ErrorClassifier).ClassifyError(err)
+				assert.Equal(t, tc.expected, expected{kind, constraint}, "%s: %v", tc.dialect, err)
+			}
+		}
+	})
+
+	t.Run("Querier", func(t *testing.T) {
+		db := setupDB(t)
+		defer teardown(t, db)
+
+		if _, ok := db.Dialect.(reform.ErrorClassifier); !ok {
+			t.Skipf("%s does not implement reform.ErrorClassifier", db.Dialect)
+		}
+
+		err := db.Insert(&PersonProject{PersonID: 102, ProjectID: "baron"})
+		_, ok := reform.IsUniqueViolation(err)
+		assert.True(t, ok, "%+v", err)
+		_, ok = reform.IsForeignKeyViolation(err)
+		assert.False(t, ok)
+
+		err = db.Insert(&PersonProject{PersonID: 1000000, ProjectID: "baron"})
+		_, ok = reform.IsForeignKeyViolation(err)
+		assert.True(t, ok, "%+v", err)
+
+		_, err = db.Exec(fmt.Sprintf("INSERT INTO %s (%s, %s) VALUES (NULL, %s)",
+			db.QualifiedView(PersonTable), db.QuoteIdentifier("name"), db.QuoteIdentifier("created_at"), db.Placeholder(1)),
+			time.Now().UTC())
+		_, ok = reform.IsNotNullViolation(err)
+		assert.True(t, ok, "%+v", err)
+
+		assert.False(t, reform.IsSerializationFailure(err))
+		assert.False(t, reform.IsDeadlock(err))
+
+		// errors not returned by Querier are not classified
+		_, ok = reform.IsNotNullViolation(errors.Unwrap(err))
+		assert.False(t, ok)
+	})
+}
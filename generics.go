@@ -0,0 +1,135 @@
+package reform
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// newStruct makes a new struct for given view and converts it to T.
+func newStruct[T Struct](view View) (T, error) {
+	str := view.NewStruct()
+	res, ok := str.(T)
+	if !ok {
+		return res, fmt.Errorf("reform: %s makes %T, not %T", view.Name(), str, res)
+	}
+	return res, nil
+}
+
+// newRecord makes a new record for given table and converts it to T.
+func newRecord[T Record](table Table) (T, error) {
+	record := table.NewRecord()
+	res, ok := record.(T)
+	if !ok {
+		return res, fmt.Errorf("reform: %s makes %T, not %T", table.Name(), record, res)
+	}
+	return res, nil
+}
+
+// SelectOne queries view with tail and args and scans first result to new struct of type T,
+// typically a pointer to generated struct: SelectOne[*Person](q, PersonTable, tail, args...).
+// See Querier.SelectOneTo for details.
+//
+// If there are no rows in result, it returns zero T and ErrNoRows.
+func SelectOne[T Struct](q *Querier, view View, tail string, args ...interface{}) (T, error) {
+	str, err := newStruct[T](view)
+	if err != nil {
+		return str, err
+	}
+	if err = q.SelectOneTo(str, tail, args...); err != nil {
+		var zero T
+		return zero, err
+	}
+	return str, nil
+}
+
+// SelectAll queries view with tail and args and returns a slice of new structs of type T,
+// typically a pointer to generated struct: SelectAll[*Person](q, PersonTable, tail, args...).
+// See Querier.SelectAllFrom for details.
+//
+// In case of query error slice will be nil. If error is encountered during iteration,
+// partial result and error will be returned. Error is never ErrNoRows.
+func SelectAll[T Struct](q *Querier, view View, tail string, args ...interface{}) (structs []T, err error) {
+	// check type before query
+	if _, err = newStruct[T](view); err != nil {
+		return
+	}
+
+	var rows *sql.Rows
+	rows, err = q.SelectRows(view, tail, args...)
+	if err != nil {
+		return
+	}
+	defer func() {
+		e := rows.Close()
+		if err == nil {
+			err = e
+		}
+	}()
+
+	for {
+		str, _ := newStruct[T](view)
+		if err = q.NextRow(str, rows); err != nil {
+			break
+		}
+
+		structs = append(structs, str)
+	}
+	if err == ErrNoRows {
+		err = nil
+	}
+	return
+}
+
+// FindOne queries view with column and arg and scans first result to new struct of type T.
+// See Querier.FindOneTo for details.
+//
+// If there are no rows in result, it returns zero T and ErrNoRows.
+func FindOne[T Struct](q *Querier, view View, column string, arg interface{}) (T, error) {
+	str, err := newStruct[T](view)
+	if err != nil {
+		return str, err
+	}
+	if err = q.FindOneTo(str, column, arg); err != nil {
+		var zero T
+		return zero, err
+	}
+	return str, nil
+}
+
+// FindAll queries view with column and args and returns a slice of new structs of type T.
+// See Querier.FindAllFrom for details.
+//
+// In case of query error slice will be nil. If error is encountered during iteration,
+// partial result and error will be returned. Error is never ErrNoRows.
+func FindAll[T Struct](q *Querier, view View, column string, args ...interface{}) ([]T, error) {
+	if _, err := newStruct[T](view); err != nil {
+		return nil, err
+	}
+
+	structs, err := q.FindAllFrom(view, column, args...)
+	if structs == nil {
+		return nil, err
+	}
+	res := make([]T, len(structs))
+	for i, str := range structs {
+		res[i] = str.(T)
+	}
+	return res, err
+}
+
+// FindByPK queries table with primary key value and scans first result to new record of type T,
+// typically a pointer to generated struct: FindByPK[*Person](q, PersonTable, pk).
+// See Querier.FindByPrimaryKeyTo for details.
+//
+// If there are no rows in result, it returns zero T and ErrNoRows.
+func FindByPK[T Record](q *Querier, table Table, pk interface{}) (T, error) {
+	record, err := newRecord[T](table)
+	if err != nil {
+		return record, err
+	}
+	if err = q.FindByPrimaryKeyTo(record, pk); err != nil {
+		var zero T
+		return zero, err
+	}
+	return record, nil
+}
@@ -0,0 +1,74 @@
+package reform_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mc2soft/reform"
+	. "github.com/mc2soft/reform/internal/test/models"
+	"github.com/mc2soft/reform/where"
+)
+
+func TestGenerics(t *testing.T) {
+	db := setupDB(t)
+	defer teardown(t, db)
+
+	q := db.Querier
+
+	t.Run("SelectOne", func(t *testing.T) {
+		person, err := reform.SelectOne[*Person](q, PersonTable, "", where.Eq(PersonTable.C.ID, 102))
+		require.NoError(t, err)
+		assert.Equal(t, "Elfrieda Abbott", person.Name)
+
+		person, err = reform.SelectOne[*Person](q, PersonTable, "", where.In(PersonTable.C.ID))
+		assert.Equal(t, reform.ErrNoRows, err)
+		assert.Nil(t, person)
+	})
+
+	t.Run("SelectAll", func(t *testing.T) {
+		persons, err := reform.SelectAll[*Person](q, PersonTable, "", where.Where(where.In(PersonTable.C.ID, 101, 102, 104)).OrderBy(PersonTable.C.ID))
+		require.NoError(t, err)
+		require.Len(t, persons, 2)
+		assert.Equal(t, int32(101), persons[0].ID)
+		assert.Equal(t, int32(102), persons[1].ID)
+
+		persons, err = reform.SelectAll[*Person](q, PersonTable, "", where.In(PersonTable.C.ID))
+		assert.NoError(t, err)
+		assert.Nil(t, persons)
+	})
+
+	t.Run("FindOne", func(t *testing.T) {
+		person, err := reform.FindOne[*Person](q, PersonTable, "email", "elfrieda_abbott@example.org")
+		require.NoError(t, err)
+		assert.Equal(t, int32(102), person.ID)
+	})
+
+	t.Run("FindAll", func(t *testing.T) {
+		persons, err := reform.FindAll[*Person](q, PersonTable, "id", 101, 102)
+		require.NoError(t, err)
+		assert.Len(t, persons, 2)
+	})
+
+	t.Run("FindByPK", func(t *testing.T) {
+		person, err := reform.FindByPK[*Person](q, PersonTable, 102)
+		require.NoError(t, err)
+		assert.Equal(t, "Elfrieda Abbott", person.Name)
+
+		person, err = reform.FindByPK[*Person](q, PersonTable, -1)
+		assert.Equal(t, reform.ErrNoRows, err)
+		assert.Nil(t, person)
+	})
+
+	t.Run("WrongType", func(t *testing.T) {
+		_, err := reform.SelectOne[*Project](q, PersonTable, "")
+		assert.EqualError(t, err, "reform: people makes *models.Person, not *models.Project")
+
+		_, err = reform.SelectAll[*Project](q, PersonTable, "")
+		assert.EqualError(t, err, "reform: people makes *models.Person, not *models.Project")
+
+		_, err = reform.FindByPK[*Project](q, PersonTable, 1)
+		assert.EqualError(t, err, "reform: people makes *models.Person, not *models.Project")
+	})
+}
@@ -0,0 +1,101 @@
+package reform
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// Operation is a kind of operation intercepted by Interceptor.
+type Operation int
+
+// Operations.
+const (
+	OpExec     Operation = iota // Querier's Exec and methods using it
+	OpQuery                     // Querier's Query and methods using it
+	OpQueryRow                  // Querier's QueryRow and methods using it
+	OpBegin                     // DB's Begin, BeginTx and InTransaction-like methods
+	OpCommit                    // TX's Commit
+	OpRollback                  // TX's Rollback
+)
+
+// String returns a string representation of this operation.
+func (op Operation) String() string {
+	switch op {
+	case OpExec:
+		return "Exec"
+	case OpQuery:
+		return "Query"
+	case OpQueryRow:
+		return "QueryRow"
+	case OpBegin:
+		return "Begin"
+	case OpCommit:
+		return "Commit"
+	case OpRollback:
+		return "Rollback"
+	default:
+		return fmt.Sprintf("Operation(%d)", int(op))
+	}
+}
+
+// Call describes an operation intercepted by Interceptor.
+type Call struct {
+	Op      Operation
+	Query   string        // query text; "BEGIN", "COMMIT" or "ROLLBACK" for transaction operations
+	Args    []interface{} // query arguments
+	Tag     string        // Querier's tag
+	Replica string        // name of replica in DB's ReplicaPool the operation is sent to, empty for master
+}
+
+// CallResult is a result of intercepted operation. Only fields relevant for operation are set.
+//
+// For OpQueryRow, errors of performed operation are deferred until Row's Scan.
+// Interceptor that returns a result without calling next should set Err instead of Row:
+// it is returned by Querier's methods using QueryRow (like SelectOneTo, Count or Insert),
+// while QueryRow itself panics, as it always returns a non-nil Row.
+type CallResult struct {
+	Result sql.Result  // for OpExec
+	Rows   *sql.Rows   // for OpQuery
+	Row    *sql.Row    // for OpQueryRow
+	TX     TXInterface // for OpBegin
+	Err    error       // for all operations; see above for OpQueryRow
+}
+
+// CallHandler performs an intercepted operation.
+type CallHandler func(ctx context.Context, call Call) CallResult
+
+// Interceptor wraps operations made by DB, TX and their Queriers (see DB.AddInterceptors).
+//
+// Intercept should call next to perform an operation, possibly with a different context
+// (for example, with tracing span), query or arguments, and return its result, possibly modified.
+// It may also return a result without calling next: for example, an error for prohibited queries.
+// Query executed by next is passed to Logger; it is already routed to master or replica.
+type Interceptor interface {
+	Intercept(ctx context.Context, call Call, next CallHandler) CallResult
+}
+
+// InterceptorFunc is an adapter to allow the use of ordinary functions as Interceptor.
+type InterceptorFunc func(ctx context.Context, call Call, next CallHandler) CallResult
+
+// Intercept returns f(ctx, call, next).
+func (f InterceptorFunc) Intercept(ctx context.Context, call Call, next CallHandler) CallResult {
+	return f(ctx, call, next)
+}
+
+// intercept calls handler h for a given call via Querier's interceptors with Querier's context.
+// The first interceptor is the outermost one.
+func (q *Querier) intercept(call Call, h CallHandler) CallResult {
+	for i := len(q.interceptors) - 1; i >= 0; i-- {
+		interceptor, next := q.interceptors[i], h
+		h = func(ctx context.Context, call Call) CallResult {
+			return interceptor.Intercept(ctx, call, next)
+		}
+	}
+	return h(q.ctx, call)
+}
+
+// check interfaces
+var (
+	_ Interceptor = InterceptorFunc(nil)
+)
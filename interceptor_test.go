@@ -0,0 +1,185 @@
+package reform_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mc2soft/reform"
+	"github.com/mc2soft/reform/internal/test"
+	. "github.com/mc2soft/reform/internal/test/models"
+)
+
+// recordingInterceptor records intercepted calls.
+type recordingInterceptor struct {
+	name  string
+	calls *[]string
+}
+
+func (r recordingInterceptor) Intercept(ctx context.Context, call reform.Call, next reform.CallHandler) reform.CallResult {
+	*r.calls = append(*r.calls, r.name+" "+call.Op.String()+" "+call.Tag+" "+call.Replica)
+	return next(ctx, call)
+}
+
+func TestInterceptors(t *testing.T) {
+	db := setupDB(t)
+	defer teardown(t, db)
+
+	var calls []string
+	db.AddInterceptors(recordingInterceptor{name: "outer", calls: &calls})
+	db.AddInterceptors(recordingInterceptor{name: "inner", calls: &calls})
+	db.Querier = db.WithTag("tag")
+
+	t.Run("Order", func(t *testing.T) {
+		calls = nil
+		_, err := db.Count(PersonTable, "")
+		require.NoError(t, err)
+		_, err = db.Exec("DELETE FROM people WHERE id = -1")
+		require.NoError(t, err)
+		rows, err := db.WithTag("other").Query("SELECT 1")
+		require.NoError(t, err)
+		require.NoError(t, rows.Close())
+
+		assert.Equal(t, []string{
+			"outer QueryRow tag ", "inner QueryRow tag ",
+			"outer Exec tag ", "inner Exec tag ",
+			"outer Query other ", "inner Query other ",
+		}, calls)
+	})
+
+	t.Run("Transaction", func(t *testing.T) {
+		calls = nil
+		err := db.InTransaction(func(tx *reform.TX) error {
+			_, err := tx.Exec("DELETE FROM people WHERE id = -1")
+			return err
+		})
+		require.NoError(t, err)
+
+		tx, err := db.Begin()
+		require.NoError(t, err)
+		require.NoError(t, tx.Rollback())
+
+		assert.Equal(t, []string{
+			"outer Begin tag ", "inner Begin tag ",
+			"outer Exec  ", "inner Exec  ", // transactions don't inherit DB's tag
+			"outer Commit  ", "inner Commit  ",
+			"outer Begin tag ", "inner Begin tag ",
+			"outer Rollback  ", "inner Rollback  ",
+		}, calls)
+	})
+
+	t.Run("Replica", func(t *testing.T) {
+		replicaDB := test.ConnectToTestDB()
+		defer teardown(t, replicaDB)
+		require.NoError(t, db.Replicas().Add("replica", replicaDB.DBInterface().(*sql.DB), 1))
+		defer db.Replicas().Remove("replica")
+
+		calls = nil
+		var one int
+		require.NoError(t, db.QueryRow("SELECT 1").Scan(&one))
+		require.NoError(t, db.OnMaster().QueryRow("SELECT 1").Scan(&one))
+		tx, err := db.BeginTx(context.Background(), &sql.TxOptions{ReadOnly: true})
+		require.NoError(t, err)
+		require.NoError(t, tx.QueryRow("SELECT 1").Scan(&one))
+		require.NoError(t, tx.Rollback())
+
+		assert.Equal(t, []string{
+			"outer QueryRow tag replica", "inner QueryRow tag replica",
+			"outer QueryRow tag ", "inner QueryRow tag ",
+			"outer Begin tag replica", "inner Begin tag replica",
+			"outer QueryRow  replica", "inner QueryRow  replica",
+			"outer Rollback  replica", "inner Rollback  replica",
+		}, calls)
+	})
+}
+
+func TestInterceptorsModify(t *testing.T) {
+	db := setupDB(t)
+	defer teardown(t, db)
+
+	type key struct{}
+	errGuard := errors.New("DELETE without WHERE")
+
+	db.AddInterceptors(
+		// guard
+		reform.InterceptorFunc(func(ctx context.Context, call reform.Call, next reform.CallHandler) reform.CallResult {
+			if strings.HasPrefix(call.Query, "DELETE") && !strings.Contains(call.Query, "WHERE") {
+				return reform.CallResult{Err: errGuard}
+			}
+			return next(context.WithValue(ctx, key{}, call.Query), call)
+		}),
+
+		// rewriter
+		reform.InterceptorFunc(func(ctx context.Context, call reform.Call, next reform.CallHandler) reform.CallResult {
+			assert.Equal(t, call.Query, ctx.Value(key{}))
+			if call.Query == "SELECT 1" {
+				call.Query = "SELECT 2"
+			}
+			return next(ctx, call)
+		}),
+	)
+
+	var n int
+	require.NoError(t, db.QueryRow("SELECT 1").Scan(&n))
+	assert.Equal(t, 2, n)
+
+	_, err := db.DeleteFrom(PersonTable, "")
+	assert.Equal(t, errGuard, err)
+	count, err := db.Count(PersonTable, "")
+	require.NoError(t, err)
+	assert.NotZero(t, count)
+
+	// guard short-circuits Begin too
+	db.AddInterceptors(reform.InterceptorFunc(func(ctx context.Context, call reform.Call, next reform.CallHandler) reform.CallResult {
+		if call.Op == reform.OpBegin {
+			return reform.CallResult{Err: errGuard}
+		}
+		return next(ctx, call)
+	}))
+	_, err = db.Begin()
+	assert.Equal(t, errGuard, err)
+}
+
+func TestInterceptorsShortCircuit(t *testing.T) {
+	db := setupDB(t)
+	defer teardown(t, db)
+
+	errGuard := errors.New("people are forbidden")
+	db.AddInterceptors(reform.InterceptorFunc(func(ctx context.Context, call reform.Call, next reform.CallHandler) reform.CallResult {
+		if strings.Contains(call.Query, "people") {
+			return reform.CallResult{Err: errGuard}
+		}
+		return next(ctx, call)
+	}))
+
+	t.Run("QueryRow", func(t *testing.T) {
+		_, err := db.Count(PersonTable, "")
+		assert.Equal(t, errGuard, err)
+		assert.Equal(t, errGuard, db.FindByPrimaryKeyTo(new(Person), 1))
+		assert.Equal(t, errGuard, db.SelectOneTo(new(Person), ""))
+		assert.Equal(t, errGuard, db.Insert(&Person{Name: "Alexey Palazhchenko"}))
+
+		assert.PanicsWithValue(t, "reform: interceptor returned no Row for QueryRow (error: people are forbidden)", func() {
+			db.QueryRow("SELECT COUNT(*) FROM people")
+		})
+
+		var n int
+		require.NoError(t, db.QueryRow("SELECT 1").Scan(&n))
+		assert.Equal(t, 1, n)
+	})
+
+	t.Run("Query", func(t *testing.T) {
+		rows, err := db.Query("SELECT id FROM people")
+		assert.Equal(t, errGuard, err)
+		assert.Nil(t, rows)
+
+		structs, err := db.SelectAllFrom(PersonTable, "")
+		assert.Equal(t, errGuard, err)
+		assert.Nil(t, structs)
+	})
+}
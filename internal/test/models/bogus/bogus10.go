@@ -4,6 +4,6 @@ package bogus
 
 // Bogus10 is used for testing. reform:bogus
 type Bogus10 struct {
-	Bogus1 string `reform:"bogus1,pk"`
-	Bogus2 string `reform:"bogus2,pk"` // field with "reform:" tag with duplicate pk label should generate error
+	Bogus1 string  `reform:"bogus1,pk"`
+	Bogus2 *string `reform:"bogus2,pk"` // pointer field with "reform:" tag and pk label in composite primary key should generate error
 }
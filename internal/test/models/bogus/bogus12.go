@@ -0,0 +1,9 @@
+package bogus
+
+//go:generate reform
+
+// Bogus12 is used for testing. reform:bogus
+type Bogus12 struct {
+	ID    int32  `reform:"id,pk"`
+	Bogus string `reform:"bogus,version"` // non-integer field with "version" label should generate error
+}
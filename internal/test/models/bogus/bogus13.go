@@ -0,0 +1,10 @@
+package bogus
+
+//go:generate reform
+
+// Bogus13 is used for testing. reform:bogus
+type Bogus13 struct {
+	ID     int32 `reform:"id,pk"`
+	Bogus1 int64 `reform:"bogus1,version"`
+	Bogus2 int64 `reform:"bogus2,version"` // second field with "version" label should generate error
+}
@@ -0,0 +1,8 @@
+package bogus
+
+//go:generate reform
+
+// Bogus14 is used for testing. reform:bogus
+type Bogus14 struct {
+	Bogus int64 `reform:"bogus,version"` // field with "version" label in view should generate error
+}
@@ -0,0 +1,13 @@
+package bogus
+
+import (
+	"time"
+)
+
+//go:generate reform
+
+// Bogus15 is used for testing. reform:bogus
+type Bogus15 struct {
+	ID    int32     `reform:"id,pk"`
+	Bogus time.Time `reform:"bogus,softdelete"` // non-pointer field with "softdelete" label should generate error
+}
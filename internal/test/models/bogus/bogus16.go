@@ -0,0 +1,9 @@
+package bogus
+
+//go:generate reform
+
+// Bogus16 is used for testing. reform:bogus
+type Bogus16 struct {
+	ID    int32  `reform:"id,pk"`
+	Bogus string `reform:"bogus,autoupdate"` // non-time field with "autoupdate" label should generate error
+}
@@ -0,0 +1,9 @@
+package bogus
+
+//go:generate reform
+
+// Bogus17 is used for testing. reform:bogus
+type Bogus17 struct {
+	ID    int32 `reform:"id,pk"`
+	Bogus int32 `reform:"bogus,fk=groups"` // "fk" label without referenced column should generate error
+}
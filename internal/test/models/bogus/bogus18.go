@@ -0,0 +1,8 @@
+package bogus
+
+//go:generate reform
+
+// Bogus18 is used for testing. reform:bogus
+type Bogus18 struct {
+	ID int32 `reform:"id,pk,pk"` // duplicate "pk" label should generate error
+}
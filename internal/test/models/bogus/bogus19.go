@@ -0,0 +1,9 @@
+package bogus
+
+//go:generate reform
+
+// Bogus19 is used for testing. reform:bogus
+type Bogus19 struct {
+	ID    int32 `reform:"id,pk"`
+	Bogus int32 `reform:"bogus,fk=groups.id,version"` // "fk" label combined with label other than "pk" should generate error
+}
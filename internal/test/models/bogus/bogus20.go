@@ -0,0 +1,9 @@
+package bogus
+
+//go:generate reform
+
+// Bogus20 is used for testing. reform:bogus
+type Bogus20 struct {
+	ID    int32 `reform:"id,pk"`
+	Bogus int32 `reform:"bogus,pk,fk=people.id,fk=groups.id"` // duplicate "fk" label should generate error
+}
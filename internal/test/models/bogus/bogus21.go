@@ -0,0 +1,9 @@
+package bogus
+
+//go:generate reform
+
+// Bogus21 is used for testing. reform:bogus
+type Bogus21 struct {
+	ID    int32 `reform:"id,pk"`
+	Bogus int32 `reform:"bogus,pk,fk=groups"` // "fk" label without referenced column combined with "pk" should generate error
+}
@@ -0,0 +1,8 @@
+package bogus
+
+//go:generate reform
+
+// Bogus22 is used for testing. reform:bogus
+type Bogus22 struct {
+	ID int32 `reform:"id,pk,autocreate"` // conflicting labels should generate error
+}
@@ -32,6 +32,12 @@ type Extra struct {
 	Uint8sT Uint8s     `reform:"uint8st"`
 }
 
+//reform:extra_person_project
+type ExtraPersonProject struct {
+	PersonID  int32  `reform:"person_id,pk,fk=people.id"`
+	ProjectID string `reform:"project_id,fk=projects.id,pk"`
+}
+
 //reform:not_exported
 type notExported struct {
 	ID string `reform:"id,pk"`
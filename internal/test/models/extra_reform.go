@@ -10,9 +10,28 @@ import (
 	"github.com/mc2soft/reform/parse"
 )
 
+// extraColumns contains column descriptors of extra view or table.
+type extraColumns struct {
+	ID      reform.Column
+	Name    reform.Column
+	Byte    reform.Column
+	Uint8   reform.Column
+	ByteP   reform.Column
+	Uint8P  reform.Column
+	Bytes   reform.Column
+	Uint8s  reform.Column
+	BytesA  reform.Column
+	Uint8sA reform.Column
+	BytesT  reform.Column
+	Uint8sT reform.Column
+}
+
 type extraTableType struct {
 	s parse.StructInfo
 	z []interface{}
+
+	// C contains column descriptors of that view or table.
+	C extraColumns
 }
 
 // Schema returns a schema name in SQL database ("").
@@ -58,6 +77,11 @@ func (v *extraTableType) PKColumnIndex() uint {
 	return uint(v.s.PKFieldIndex)
 }
 
+// PKColumnIndexes returns a new slice of indexes of all primary key columns for that table in SQL database.
+func (v *extraTableType) PKColumnIndexes() []uint {
+	return []uint{uint(v.s.PKFieldIndex)}
+}
+
 // ExtraTable represents extra view or table in SQL database.
 var ExtraTable = &extraTableType{
 	s: parse.StructInfo{
@@ -80,6 +104,20 @@ var ExtraTable = &extraTableType{
 		PKFieldIndex: 0,
 	},
 	z: new(Extra).Values(),
+	C: extraColumns{
+		ID:      "id",
+		Name:    "name",
+		Byte:    "byte",
+		Uint8:   "uint8",
+		ByteP:   "bytep",
+		Uint8P:  "uint8p",
+		Bytes:   "bytes",
+		Uint8s:  "uint8s",
+		BytesA:  "bytesa",
+		Uint8sA: "uint8sa",
+		BytesT:  "bytest",
+		Uint8sT: "uint8st",
+	},
 }
 
 // String returns a string representation of this struct or record.
@@ -172,6 +210,12 @@ func (s *Extra) SetPK(pk interface{}) {
 	reform.SetPK(s, pk)
 }
 
+// Changed returns names of columns which values differ from the snapshot of that record
+// taken when it was loaded or saved, or all columns if it doesn't embed reform.Snapshot.
+func (s *Extra) Changed() []string {
+	return reform.ChangedColumns(s)
+}
+
 // check interfaces
 var (
 	_ reform.View   = ExtraTable
@@ -181,9 +225,183 @@ var (
 	_ fmt.Stringer  = (*Extra)(nil)
 )
 
+// extraPersonProjectColumns contains column descriptors of extra_person_project view or table.
+type extraPersonProjectColumns struct {
+	PersonID  reform.Column
+	ProjectID reform.Column
+}
+
+type extraPersonProjectTableType struct {
+	s parse.StructInfo
+	z []interface{}
+
+	// C contains column descriptors of that view or table.
+	C extraPersonProjectColumns
+}
+
+// Schema returns a schema name in SQL database ("").
+func (v *extraPersonProjectTableType) Schema() string {
+	return v.s.SQLSchema
+}
+
+// Name returns a view or table name in SQL database ("extra_person_project").
+func (v *extraPersonProjectTableType) Name() string {
+	return v.s.SQLName
+}
+
+// Columns returns a new slice of column names for that view or table in SQL database.
+func (v *extraPersonProjectTableType) Columns() []string {
+	return []string{
+		"person_id",
+		"project_id",
+	}
+}
+
+// NewStruct makes a new struct for that view or table.
+func (v *extraPersonProjectTableType) NewStruct() reform.Struct {
+	return new(ExtraPersonProject)
+}
+
+// ForeignKeys returns a new slice of foreign keys of that view or table.
+func (v *extraPersonProjectTableType) ForeignKeys() []reform.ForeignKey {
+	return []reform.ForeignKey{
+		{Column: "person_id", RefTable: "people", RefColumn: "id"},
+		{Column: "project_id", RefTable: "projects", RefColumn: "id"},
+	}
+}
+
+// NewRecord makes a new record for that table.
+func (v *extraPersonProjectTableType) NewRecord() reform.Record {
+	return new(ExtraPersonProject)
+}
+
+// PKColumnIndex returns an index of primary key column for that table in SQL database.
+func (v *extraPersonProjectTableType) PKColumnIndex() uint {
+	return uint(v.s.PKFieldIndex)
+}
+
+// PKColumnIndexes returns a new slice of indexes of all primary key columns for that table in SQL database.
+func (v *extraPersonProjectTableType) PKColumnIndexes() []uint {
+	res := make([]uint, len(v.s.PKFieldIndexes))
+	for i, pk := range v.s.PKFieldIndexes {
+		res[i] = uint(pk)
+	}
+	return res
+}
+
+// ExtraPersonProjectTable represents extra_person_project view or table in SQL database.
+var ExtraPersonProjectTable = &extraPersonProjectTableType{
+	s: parse.StructInfo{
+		Type:    "ExtraPersonProject",
+		SQLName: "extra_person_project",
+		Fields: []parse.FieldInfo{
+			{Name: "PersonID", Type: "int32", Column: "person_id", FK: "people.id"},
+			{Name: "ProjectID", Type: "string", Column: "project_id", FK: "projects.id"},
+		},
+		PKFieldIndex:   0,
+		PKFieldIndexes: []int{0, 1},
+	},
+	z: new(ExtraPersonProject).Values(),
+	C: extraPersonProjectColumns{
+		PersonID:  "person_id",
+		ProjectID: "project_id",
+	},
+}
+
+// String returns a string representation of this struct or record.
+func (s ExtraPersonProject) String() string {
+	res := make([]string, 2)
+	res[0] = "PersonID: " + reform.Inspect(s.PersonID, true)
+	res[1] = "ProjectID: " + reform.Inspect(s.ProjectID, true)
+	return strings.Join(res, ", ")
+}
+
+// Values returns a slice of struct or record field values.
+// Returned interface{} values are never untyped nils.
+func (s *ExtraPersonProject) Values() []interface{} {
+	return []interface{}{
+		s.PersonID,
+		s.ProjectID,
+	}
+}
+
+// Pointers returns a slice of pointers to struct or record fields.
+// Returned interface{} values are never untyped nils.
+func (s *ExtraPersonProject) Pointers() []interface{} {
+	return []interface{}{
+		&s.PersonID,
+		&s.ProjectID,
+	}
+}
+
+// View returns View object for that struct.
+func (s *ExtraPersonProject) View() reform.View {
+	return ExtraPersonProjectTable
+}
+
+// Table returns Table object for that record.
+func (s *ExtraPersonProject) Table() reform.Table {
+	return ExtraPersonProjectTable
+}
+
+// PKValue returns a slice of values of composite primary key for that record.
+// Returned interface{} value is never untyped nil.
+func (s *ExtraPersonProject) PKValue() interface{} {
+	return []interface{}{
+		s.PersonID,
+		s.ProjectID,
+	}
+}
+
+// PKPointer returns a slice of pointers to composite primary key fields for that record.
+// Returned interface{} value is never untyped nil.
+func (s *ExtraPersonProject) PKPointer() interface{} {
+	return []interface{}{
+		&s.PersonID,
+		&s.ProjectID,
+	}
+}
+
+// HasPK returns true if record has at least one composite primary key field set to non-zero value, false otherwise.
+func (s *ExtraPersonProject) HasPK() bool {
+	return s.PersonID != ExtraPersonProjectTable.z[ExtraPersonProjectTable.s.PKFieldIndexes[0]] ||
+		s.ProjectID != ExtraPersonProjectTable.z[ExtraPersonProjectTable.s.PKFieldIndexes[1]]
+}
+
+// SetPK sets record composite primary key from a slice of values, if possible.
+//
+// Deprecated: prefer direct field assignment where possible.
+func (s *ExtraPersonProject) SetPK(pk interface{}) {
+	reform.SetPK(s, pk)
+}
+
+// Changed returns names of columns which values differ from the snapshot of that record
+// taken when it was loaded or saved, or all columns if it doesn't embed reform.Snapshot.
+func (s *ExtraPersonProject) Changed() []string {
+	return reform.ChangedColumns(s)
+}
+
+// check interfaces
+var (
+	_ reform.View            = ExtraPersonProjectTable
+	_ reform.Struct          = (*ExtraPersonProject)(nil)
+	_ reform.ForeignKeysView = ExtraPersonProjectTable
+	_ reform.Table           = ExtraPersonProjectTable
+	_ reform.Record          = (*ExtraPersonProject)(nil)
+	_ fmt.Stringer           = (*ExtraPersonProject)(nil)
+)
+
+// notExportedColumns contains column descriptors of not_exported view or table.
+type notExportedColumns struct {
+	ID reform.Column
+}
+
 type notExportedTableType struct {
 	s parse.StructInfo
 	z []interface{}
+
+	// C contains column descriptors of that view or table.
+	C notExportedColumns
 }
 
 // Schema returns a schema name in SQL database ("").
@@ -218,6 +436,11 @@ func (v *notExportedTableType) PKColumnIndex() uint {
 	return uint(v.s.PKFieldIndex)
 }
 
+// PKColumnIndexes returns a new slice of indexes of all primary key columns for that table in SQL database.
+func (v *notExportedTableType) PKColumnIndexes() []uint {
+	return []uint{uint(v.s.PKFieldIndex)}
+}
+
 // notExportedTable represents not_exported view or table in SQL database.
 var notExportedTable = &notExportedTableType{
 	s: parse.StructInfo{
@@ -229,6 +452,9 @@ var notExportedTable = &notExportedTableType{
 		PKFieldIndex: 0,
 	},
 	z: new(notExported).Values(),
+	C: notExportedColumns{
+		ID: "id",
+	},
 }
 
 // String returns a string representation of this struct or record.
@@ -288,6 +514,12 @@ func (s *notExported) SetPK(pk interface{}) {
 	reform.SetPK(s, pk)
 }
 
+// Changed returns names of columns which values differ from the snapshot of that record
+// taken when it was loaded or saved, or all columns if it doesn't embed reform.Snapshot.
+func (s *notExported) Changed() []string {
+	return reform.ChangedColumns(s)
+}
+
 // check interfaces
 var (
 	_ reform.View   = notExportedTable
@@ -299,5 +531,6 @@ var (
 
 func init() {
 	parse.AssertUpToDate(&ExtraTable.s, new(Extra))
+	parse.AssertUpToDate(&ExtraPersonProjectTable.s, new(ExtraPersonProject))
 	parse.AssertUpToDate(&notExportedTable.s, new(notExported))
 }
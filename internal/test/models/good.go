@@ -103,8 +103,8 @@ func (p *Project) AfterFind() error {
 
 // PersonProject represents row in table person_project. reform:person_project
 type PersonProject struct {
-	PersonID  int32  `reform:"person_id"`
-	ProjectID string `reform:"project_id"`
+	PersonID  int32  `reform:"person_id,fk=people.id"`
+	ProjectID string `reform:"project_id,fk=projects.id"`
 }
 
 // reform:id_only
@@ -124,6 +124,25 @@ type LegacyPerson struct {
 	Name *string `reform:"name"`
 }
 
+//reform:composite_pk
+type CompositePK struct {
+	I    int32  `reform:"i,pk"`
+	Name string `reform:"name"`
+	ID   string `reform:"id,pk"`
+}
+
+//reform:documents
+type Document struct {
+	reform.Snapshot
+
+	ID          int32      `reform:"id,pk"`
+	Name        string     `reform:"name"`
+	LockVersion int64      `reform:"lock_version,version"`
+	DeletedAt   *time.Time `reform:"deleted_at,softdelete"`
+	CreatedAt   time.Time  `reform:"created_at,autocreate"`
+	UpdatedAt   *time.Time `reform:"updated_at,autoupdate"`
+}
+
 // check interfaces
 var (
 	_ reform.BeforeInserter = (*Person)(nil)
@@ -10,9 +10,22 @@ import (
 	"github.com/mc2soft/reform/parse"
 )
 
+// personColumns contains column descriptors of people view or table.
+type personColumns struct {
+	ID        reform.Column
+	GroupID   reform.Column
+	Name      reform.Column
+	Email     reform.Column
+	CreatedAt reform.Column
+	UpdatedAt reform.Column
+}
+
 type personTableType struct {
 	s parse.StructInfo
 	z []interface{}
+
+	// C contains column descriptors of that view or table.
+	C personColumns
 }
 
 // Schema returns a schema name in SQL database ("").
@@ -52,6 +65,11 @@ func (v *personTableType) PKColumnIndex() uint {
 	return uint(v.s.PKFieldIndex)
 }
 
+// PKColumnIndexes returns a new slice of indexes of all primary key columns for that table in SQL database.
+func (v *personTableType) PKColumnIndexes() []uint {
+	return []uint{uint(v.s.PKFieldIndex)}
+}
+
 // PersonTable represents people view or table in SQL database.
 var PersonTable = &personTableType{
 	s: parse.StructInfo{
@@ -68,6 +86,14 @@ var PersonTable = &personTableType{
 		PKFieldIndex: 0,
 	},
 	z: new(Person).Values(),
+	C: personColumns{
+		ID:        "id",
+		GroupID:   "group_id",
+		Name:      "name",
+		Email:     "email",
+		CreatedAt: "created_at",
+		UpdatedAt: "updated_at",
+	},
 }
 
 // String returns a string representation of this struct or record.
@@ -142,6 +168,12 @@ func (s *Person) SetPK(pk interface{}) {
 	reform.SetPK(s, pk)
 }
 
+// Changed returns names of columns which values differ from the snapshot of that record
+// taken when it was loaded or saved, or all columns if it doesn't embed reform.Snapshot.
+func (s *Person) Changed() []string {
+	return reform.ChangedColumns(s)
+}
+
 // check interfaces
 var (
 	_ reform.View   = PersonTable
@@ -151,9 +183,20 @@ var (
 	_ fmt.Stringer  = (*Person)(nil)
 )
 
+// projectColumns contains column descriptors of projects view or table.
+type projectColumns struct {
+	Name  reform.Column
+	ID    reform.Column
+	Start reform.Column
+	End   reform.Column
+}
+
 type projectTableType struct {
 	s parse.StructInfo
 	z []interface{}
+
+	// C contains column descriptors of that view or table.
+	C projectColumns
 }
 
 // Schema returns a schema name in SQL database ("").
@@ -191,6 +234,11 @@ func (v *projectTableType) PKColumnIndex() uint {
 	return uint(v.s.PKFieldIndex)
 }
 
+// PKColumnIndexes returns a new slice of indexes of all primary key columns for that table in SQL database.
+func (v *projectTableType) PKColumnIndexes() []uint {
+	return []uint{uint(v.s.PKFieldIndex)}
+}
+
 // ProjectTable represents projects view or table in SQL database.
 var ProjectTable = &projectTableType{
 	s: parse.StructInfo{
@@ -205,6 +253,12 @@ var ProjectTable = &projectTableType{
 		PKFieldIndex: 1,
 	},
 	z: new(Project).Values(),
+	C: projectColumns{
+		Name:  "name",
+		ID:    "id",
+		Start: "start",
+		End:   "end",
+	},
 }
 
 // String returns a string representation of this struct or record.
@@ -273,6 +327,12 @@ func (s *Project) SetPK(pk interface{}) {
 	reform.SetPK(s, pk)
 }
 
+// Changed returns names of columns which values differ from the snapshot of that record
+// taken when it was loaded or saved, or all columns if it doesn't embed reform.Snapshot.
+func (s *Project) Changed() []string {
+	return reform.ChangedColumns(s)
+}
+
 // check interfaces
 var (
 	_ reform.View   = ProjectTable
@@ -282,9 +342,18 @@ var (
 	_ fmt.Stringer  = (*Project)(nil)
 )
 
+// personProjectColumns contains column descriptors of person_project view or table.
+type personProjectColumns struct {
+	PersonID  reform.Column
+	ProjectID reform.Column
+}
+
 type personProjectViewType struct {
 	s parse.StructInfo
 	z []interface{}
+
+	// C contains column descriptors of that view or table.
+	C personProjectColumns
 }
 
 // Schema returns a schema name in SQL database ("").
@@ -310,18 +379,30 @@ func (v *personProjectViewType) NewStruct() reform.Struct {
 	return new(PersonProject)
 }
 
+// ForeignKeys returns a new slice of foreign keys of that view or table.
+func (v *personProjectViewType) ForeignKeys() []reform.ForeignKey {
+	return []reform.ForeignKey{
+		{Column: "person_id", RefTable: "people", RefColumn: "id"},
+		{Column: "project_id", RefTable: "projects", RefColumn: "id"},
+	}
+}
+
 // PersonProjectView represents person_project view or table in SQL database.
 var PersonProjectView = &personProjectViewType{
 	s: parse.StructInfo{
 		Type:    "PersonProject",
 		SQLName: "person_project",
 		Fields: []parse.FieldInfo{
-			{Name: "PersonID", Type: "int32", Column: "person_id"},
-			{Name: "ProjectID", Type: "string", Column: "project_id"},
+			{Name: "PersonID", Type: "int32", Column: "person_id", FK: "people.id"},
+			{Name: "ProjectID", Type: "string", Column: "project_id", FK: "projects.id"},
 		},
 		PKFieldIndex: -1,
 	},
 	z: new(PersonProject).Values(),
+	C: personProjectColumns{
+		PersonID:  "person_id",
+		ProjectID: "project_id",
+	},
 }
 
 // String returns a string representation of this struct or record.
@@ -357,50 +438,64 @@ func (s *PersonProject) View() reform.View {
 
 // check interfaces
 var (
-	_ reform.View   = PersonProjectView
-	_ reform.Struct = (*PersonProject)(nil)
-	_ fmt.Stringer  = (*PersonProject)(nil)
+	_ reform.View            = PersonProjectView
+	_ reform.Struct          = (*PersonProject)(nil)
+	_ reform.ForeignKeysView = PersonProjectView
+	_ fmt.Stringer           = (*PersonProject)(nil)
 )
 
-type iDOnlyTableType struct {
+// idOnlyColumns contains column descriptors of id_only view or table.
+type idOnlyColumns struct {
+	ID reform.Column
+}
+
+type idOnlyTableType struct {
 	s parse.StructInfo
 	z []interface{}
+
+	// C contains column descriptors of that view or table.
+	C idOnlyColumns
 }
 
 // Schema returns a schema name in SQL database ("").
-func (v *iDOnlyTableType) Schema() string {
+func (v *idOnlyTableType) Schema() string {
 	return v.s.SQLSchema
 }
 
 // Name returns a view or table name in SQL database ("id_only").
-func (v *iDOnlyTableType) Name() string {
+func (v *idOnlyTableType) Name() string {
 	return v.s.SQLName
 }
 
 // Columns returns a new slice of column names for that view or table in SQL database.
-func (v *iDOnlyTableType) Columns() []string {
+func (v *idOnlyTableType) Columns() []string {
 	return []string{
 		"id",
 	}
 }
 
 // NewStruct makes a new struct for that view or table.
-func (v *iDOnlyTableType) NewStruct() reform.Struct {
+func (v *idOnlyTableType) NewStruct() reform.Struct {
 	return new(IDOnly)
 }
 
 // NewRecord makes a new record for that table.
-func (v *iDOnlyTableType) NewRecord() reform.Record {
+func (v *idOnlyTableType) NewRecord() reform.Record {
 	return new(IDOnly)
 }
 
 // PKColumnIndex returns an index of primary key column for that table in SQL database.
-func (v *iDOnlyTableType) PKColumnIndex() uint {
+func (v *idOnlyTableType) PKColumnIndex() uint {
 	return uint(v.s.PKFieldIndex)
 }
 
+// PKColumnIndexes returns a new slice of indexes of all primary key columns for that table in SQL database.
+func (v *idOnlyTableType) PKColumnIndexes() []uint {
+	return []uint{uint(v.s.PKFieldIndex)}
+}
+
 // IDOnlyTable represents id_only view or table in SQL database.
-var IDOnlyTable = &iDOnlyTableType{
+var IDOnlyTable = &idOnlyTableType{
 	s: parse.StructInfo{
 		Type:    "IDOnly",
 		SQLName: "id_only",
@@ -410,6 +505,9 @@ var IDOnlyTable = &iDOnlyTableType{
 		PKFieldIndex: 0,
 	},
 	z: new(IDOnly).Values(),
+	C: idOnlyColumns{
+		ID: "id",
+	},
 }
 
 // String returns a string representation of this struct or record.
@@ -469,6 +567,12 @@ func (s *IDOnly) SetPK(pk interface{}) {
 	reform.SetPK(s, pk)
 }
 
+// Changed returns names of columns which values differ from the snapshot of that record
+// taken when it was loaded or saved, or all columns if it doesn't embed reform.Snapshot.
+func (s *IDOnly) Changed() []string {
+	return reform.ChangedColumns(s)
+}
+
 // check interfaces
 var (
 	_ reform.View   = IDOnlyTable
@@ -478,9 +582,18 @@ var (
 	_ fmt.Stringer  = (*IDOnly)(nil)
 )
 
+// constraintsColumns contains column descriptors of constraints view or table.
+type constraintsColumns struct {
+	I  reform.Column
+	ID reform.Column
+}
+
 type constraintsTableType struct {
 	s parse.StructInfo
 	z []interface{}
+
+	// C contains column descriptors of that view or table.
+	C constraintsColumns
 }
 
 // Schema returns a schema name in SQL database ("").
@@ -516,6 +629,11 @@ func (v *constraintsTableType) PKColumnIndex() uint {
 	return uint(v.s.PKFieldIndex)
 }
 
+// PKColumnIndexes returns a new slice of indexes of all primary key columns for that table in SQL database.
+func (v *constraintsTableType) PKColumnIndexes() []uint {
+	return []uint{uint(v.s.PKFieldIndex)}
+}
+
 // ConstraintsTable represents constraints view or table in SQL database.
 var ConstraintsTable = &constraintsTableType{
 	s: parse.StructInfo{
@@ -528,6 +646,10 @@ var ConstraintsTable = &constraintsTableType{
 		PKFieldIndex: 1,
 	},
 	z: new(Constraints).Values(),
+	C: constraintsColumns{
+		I:  "i",
+		ID: "id",
+	},
 }
 
 // String returns a string representation of this struct or record.
@@ -590,6 +712,12 @@ func (s *Constraints) SetPK(pk interface{}) {
 	reform.SetPK(s, pk)
 }
 
+// Changed returns names of columns which values differ from the snapshot of that record
+// taken when it was loaded or saved, or all columns if it doesn't embed reform.Snapshot.
+func (s *Constraints) Changed() []string {
+	return reform.ChangedColumns(s)
+}
+
 // check interfaces
 var (
 	_ reform.View   = ConstraintsTable
@@ -599,9 +727,18 @@ var (
 	_ fmt.Stringer  = (*Constraints)(nil)
 )
 
+// legacyPersonColumns contains column descriptors of people view or table.
+type legacyPersonColumns struct {
+	ID   reform.Column
+	Name reform.Column
+}
+
 type legacyPersonTableType struct {
 	s parse.StructInfo
 	z []interface{}
+
+	// C contains column descriptors of that view or table.
+	C legacyPersonColumns
 }
 
 // Schema returns a schema name in SQL database ("legacy").
@@ -637,6 +774,11 @@ func (v *legacyPersonTableType) PKColumnIndex() uint {
 	return uint(v.s.PKFieldIndex)
 }
 
+// PKColumnIndexes returns a new slice of indexes of all primary key columns for that table in SQL database.
+func (v *legacyPersonTableType) PKColumnIndexes() []uint {
+	return []uint{uint(v.s.PKFieldIndex)}
+}
+
 // LegacyPersonTable represents people view or table in SQL database.
 var LegacyPersonTable = &legacyPersonTableType{
 	s: parse.StructInfo{
@@ -650,6 +792,10 @@ var LegacyPersonTable = &legacyPersonTableType{
 		PKFieldIndex: 0,
 	},
 	z: new(LegacyPerson).Values(),
+	C: legacyPersonColumns{
+		ID:   "id",
+		Name: "name",
+	},
 }
 
 // String returns a string representation of this struct or record.
@@ -712,6 +858,12 @@ func (s *LegacyPerson) SetPK(pk interface{}) {
 	reform.SetPK(s, pk)
 }
 
+// Changed returns names of columns which values differ from the snapshot of that record
+// taken when it was loaded or saved, or all columns if it doesn't embed reform.Snapshot.
+func (s *LegacyPerson) Changed() []string {
+	return reform.ChangedColumns(s)
+}
+
 // check interfaces
 var (
 	_ reform.View   = LegacyPersonTable
@@ -721,6 +873,367 @@ var (
 	_ fmt.Stringer  = (*LegacyPerson)(nil)
 )
 
+// compositePKColumns contains column descriptors of composite_pk view or table.
+type compositePKColumns struct {
+	I    reform.Column
+	Name reform.Column
+	ID   reform.Column
+}
+
+type compositePKTableType struct {
+	s parse.StructInfo
+	z []interface{}
+
+	// C contains column descriptors of that view or table.
+	C compositePKColumns
+}
+
+// Schema returns a schema name in SQL database ("").
+func (v *compositePKTableType) Schema() string {
+	return v.s.SQLSchema
+}
+
+// Name returns a view or table name in SQL database ("composite_pk").
+func (v *compositePKTableType) Name() string {
+	return v.s.SQLName
+}
+
+// Columns returns a new slice of column names for that view or table in SQL database.
+func (v *compositePKTableType) Columns() []string {
+	return []string{
+		"i",
+		"name",
+		"id",
+	}
+}
+
+// NewStruct makes a new struct for that view or table.
+func (v *compositePKTableType) NewStruct() reform.Struct {
+	return new(CompositePK)
+}
+
+// NewRecord makes a new record for that table.
+func (v *compositePKTableType) NewRecord() reform.Record {
+	return new(CompositePK)
+}
+
+// PKColumnIndex returns an index of primary key column for that table in SQL database.
+func (v *compositePKTableType) PKColumnIndex() uint {
+	return uint(v.s.PKFieldIndex)
+}
+
+// PKColumnIndexes returns a new slice of indexes of all primary key columns for that table in SQL database.
+func (v *compositePKTableType) PKColumnIndexes() []uint {
+	res := make([]uint, len(v.s.PKFieldIndexes))
+	for i, pk := range v.s.PKFieldIndexes {
+		res[i] = uint(pk)
+	}
+	return res
+}
+
+// CompositePKTable represents composite_pk view or table in SQL database.
+var CompositePKTable = &compositePKTableType{
+	s: parse.StructInfo{
+		Type:    "CompositePK",
+		SQLName: "composite_pk",
+		Fields: []parse.FieldInfo{
+			{Name: "I", Type: "int32", Column: "i"},
+			{Name: "Name", Type: "string", Column: "name"},
+			{Name: "ID", Type: "string", Column: "id"},
+		},
+		PKFieldIndex:   0,
+		PKFieldIndexes: []int{0, 2},
+	},
+	z: new(CompositePK).Values(),
+	C: compositePKColumns{
+		I:    "i",
+		Name: "name",
+		ID:   "id",
+	},
+}
+
+// String returns a string representation of this struct or record.
+func (s CompositePK) String() string {
+	res := make([]string, 3)
+	res[0] = "I: " + reform.Inspect(s.I, true)
+	res[1] = "Name: " + reform.Inspect(s.Name, true)
+	res[2] = "ID: " + reform.Inspect(s.ID, true)
+	return strings.Join(res, ", ")
+}
+
+// Values returns a slice of struct or record field values.
+// Returned interface{} values are never untyped nils.
+func (s *CompositePK) Values() []interface{} {
+	return []interface{}{
+		s.I,
+		s.Name,
+		s.ID,
+	}
+}
+
+// Pointers returns a slice of pointers to struct or record fields.
+// Returned interface{} values are never untyped nils.
+func (s *CompositePK) Pointers() []interface{} {
+	return []interface{}{
+		&s.I,
+		&s.Name,
+		&s.ID,
+	}
+}
+
+// View returns View object for that struct.
+func (s *CompositePK) View() reform.View {
+	return CompositePKTable
+}
+
+// Table returns Table object for that record.
+func (s *CompositePK) Table() reform.Table {
+	return CompositePKTable
+}
+
+// PKValue returns a slice of values of composite primary key for that record.
+// Returned interface{} value is never untyped nil.
+func (s *CompositePK) PKValue() interface{} {
+	return []interface{}{
+		s.I,
+		s.ID,
+	}
+}
+
+// PKPointer returns a slice of pointers to composite primary key fields for that record.
+// Returned interface{} value is never untyped nil.
+func (s *CompositePK) PKPointer() interface{} {
+	return []interface{}{
+		&s.I,
+		&s.ID,
+	}
+}
+
+// HasPK returns true if record has at least one composite primary key field set to non-zero value, false otherwise.
+func (s *CompositePK) HasPK() bool {
+	return s.I != CompositePKTable.z[CompositePKTable.s.PKFieldIndexes[0]] ||
+		s.ID != CompositePKTable.z[CompositePKTable.s.PKFieldIndexes[1]]
+}
+
+// SetPK sets record composite primary key from a slice of values, if possible.
+//
+// Deprecated: prefer direct field assignment where possible.
+func (s *CompositePK) SetPK(pk interface{}) {
+	reform.SetPK(s, pk)
+}
+
+// Changed returns names of columns which values differ from the snapshot of that record
+// taken when it was loaded or saved, or all columns if it doesn't embed reform.Snapshot.
+func (s *CompositePK) Changed() []string {
+	return reform.ChangedColumns(s)
+}
+
+// check interfaces
+var (
+	_ reform.View   = CompositePKTable
+	_ reform.Struct = (*CompositePK)(nil)
+	_ reform.Table  = CompositePKTable
+	_ reform.Record = (*CompositePK)(nil)
+	_ fmt.Stringer  = (*CompositePK)(nil)
+)
+
+// documentColumns contains column descriptors of documents view or table.
+type documentColumns struct {
+	ID          reform.Column
+	Name        reform.Column
+	LockVersion reform.Column
+	DeletedAt   reform.Column
+	CreatedAt   reform.Column
+	UpdatedAt   reform.Column
+}
+
+type documentTableType struct {
+	s parse.StructInfo
+	z []interface{}
+
+	// C contains column descriptors of that view or table.
+	C documentColumns
+}
+
+// Schema returns a schema name in SQL database ("").
+func (v *documentTableType) Schema() string {
+	return v.s.SQLSchema
+}
+
+// Name returns a view or table name in SQL database ("documents").
+func (v *documentTableType) Name() string {
+	return v.s.SQLName
+}
+
+// Columns returns a new slice of column names for that view or table in SQL database.
+func (v *documentTableType) Columns() []string {
+	return []string{
+		"id",
+		"name",
+		"lock_version",
+		"deleted_at",
+		"created_at",
+		"updated_at",
+	}
+}
+
+// NewStruct makes a new struct for that view or table.
+func (v *documentTableType) NewStruct() reform.Struct {
+	return new(Document)
+}
+
+// NewRecord makes a new record for that table.
+func (v *documentTableType) NewRecord() reform.Record {
+	return new(Document)
+}
+
+// PKColumnIndex returns an index of primary key column for that table in SQL database.
+func (v *documentTableType) PKColumnIndex() uint {
+	return uint(v.s.PKFieldIndex)
+}
+
+// PKColumnIndexes returns a new slice of indexes of all primary key columns for that table in SQL database.
+func (v *documentTableType) PKColumnIndexes() []uint {
+	return []uint{uint(v.s.PKFieldIndex)}
+}
+
+// VersionColumnIndex returns an index of version column for that table in SQL database.
+func (v *documentTableType) VersionColumnIndex() uint {
+	return uint(v.s.VersionFieldIndex())
+}
+
+// SoftDeleteColumnIndex returns an index of soft delete column for that table in SQL database.
+func (v *documentTableType) SoftDeleteColumnIndex() uint {
+	return 3
+}
+
+// AutoCreateColumnIndex returns an index of column set on insert for that table in SQL database.
+func (v *documentTableType) AutoCreateColumnIndex() uint {
+	return 4
+}
+
+// AutoUpdateColumnIndex returns an index of column set on update for that table in SQL database.
+func (v *documentTableType) AutoUpdateColumnIndex() uint {
+	return 5
+}
+
+// DocumentTable represents documents view or table in SQL database.
+var DocumentTable = &documentTableType{
+	s: parse.StructInfo{
+		Type:    "Document",
+		SQLName: "documents",
+		Fields: []parse.FieldInfo{
+			{Name: "ID", Type: "int32", Column: "id"},
+			{Name: "Name", Type: "string", Column: "name"},
+			{Name: "LockVersion", Type: "int64", Column: "lock_version", Version: true},
+			{Name: "DeletedAt", Type: "*time.Time", Column: "deleted_at", SoftDelete: true},
+			{Name: "CreatedAt", Type: "time.Time", Column: "created_at", AutoCreate: true},
+			{Name: "UpdatedAt", Type: "*time.Time", Column: "updated_at", AutoUpdate: true},
+		},
+		PKFieldIndex: 0,
+	},
+	z: new(Document).Values(),
+	C: documentColumns{
+		ID:          "id",
+		Name:        "name",
+		LockVersion: "lock_version",
+		DeletedAt:   "deleted_at",
+		CreatedAt:   "created_at",
+		UpdatedAt:   "updated_at",
+	},
+}
+
+// String returns a string representation of this struct or record.
+func (s Document) String() string {
+	res := make([]string, 6)
+	res[0] = "ID: " + reform.Inspect(s.ID, true)
+	res[1] = "Name: " + reform.Inspect(s.Name, true)
+	res[2] = "LockVersion: " + reform.Inspect(s.LockVersion, true)
+	res[3] = "DeletedAt: " + reform.Inspect(s.DeletedAt, true)
+	res[4] = "CreatedAt: " + reform.Inspect(s.CreatedAt, true)
+	res[5] = "UpdatedAt: " + reform.Inspect(s.UpdatedAt, true)
+	return strings.Join(res, ", ")
+}
+
+// Values returns a slice of struct or record field values.
+// Returned interface{} values are never untyped nils.
+func (s *Document) Values() []interface{} {
+	return []interface{}{
+		s.ID,
+		s.Name,
+		s.LockVersion,
+		s.DeletedAt,
+		s.CreatedAt,
+		s.UpdatedAt,
+	}
+}
+
+// Pointers returns a slice of pointers to struct or record fields.
+// Returned interface{} values are never untyped nils.
+func (s *Document) Pointers() []interface{} {
+	return []interface{}{
+		&s.ID,
+		&s.Name,
+		&s.LockVersion,
+		&s.DeletedAt,
+		&s.CreatedAt,
+		&s.UpdatedAt,
+	}
+}
+
+// View returns View object for that struct.
+func (s *Document) View() reform.View {
+	return DocumentTable
+}
+
+// Table returns Table object for that record.
+func (s *Document) Table() reform.Table {
+	return DocumentTable
+}
+
+// PKValue returns a value of primary key for that record.
+// Returned interface{} value is never untyped nil.
+func (s *Document) PKValue() interface{} {
+	return s.ID
+}
+
+// PKPointer returns a pointer to primary key field for that record.
+// Returned interface{} value is never untyped nil.
+func (s *Document) PKPointer() interface{} {
+	return &s.ID
+}
+
+// HasPK returns true if record has non-zero primary key set, false otherwise.
+func (s *Document) HasPK() bool {
+	return s.ID != DocumentTable.z[DocumentTable.s.PKFieldIndex]
+}
+
+// SetPK sets record primary key, if possible.
+//
+// Deprecated: prefer direct field assignment where possible: s.ID = pk.
+func (s *Document) SetPK(pk interface{}) {
+	reform.SetPK(s, pk)
+}
+
+// Changed returns names of columns which values differ from the snapshot of that record
+// taken when it was loaded or saved, or all columns if it doesn't embed reform.Snapshot.
+func (s *Document) Changed() []string {
+	return reform.ChangedColumns(s)
+}
+
+// check interfaces
+var (
+	_ reform.View            = DocumentTable
+	_ reform.Struct          = (*Document)(nil)
+	_ reform.Table           = DocumentTable
+	_ reform.Record          = (*Document)(nil)
+	_ reform.VersionedTable  = DocumentTable
+	_ reform.SoftDeleteTable = DocumentTable
+	_ reform.AutoCreateTable = DocumentTable
+	_ reform.AutoUpdateTable = DocumentTable
+	_ fmt.Stringer           = (*Document)(nil)
+)
+
 func init() {
 	parse.AssertUpToDate(&PersonTable.s, new(Person))
 	parse.AssertUpToDate(&ProjectTable.s, new(Project))
@@ -728,4 +1241,6 @@ func init() {
 	parse.AssertUpToDate(&IDOnlyTable.s, new(IDOnly))
 	parse.AssertUpToDate(&ConstraintsTable.s, new(Constraints))
 	parse.AssertUpToDate(&LegacyPersonTable.s, new(LegacyPerson))
+	parse.AssertUpToDate(&CompositePKTable.s, new(CompositePK))
+	parse.AssertUpToDate(&DocumentTable.s, new(Document))
 }
@@ -1,13 +1,20 @@
 package reform_test
 
 import (
+	"context"
 	"database/sql"
 	"log"
 	"os"
+	"testing"
+	"time"
 
 	"github.com/AlekSi/pointer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
 	"github.com/mc2soft/reform"
 	"github.com/mc2soft/reform/dialects/postgresql"
+	"github.com/mc2soft/reform/internal/test"
 	. "github.com/mc2soft/reform/internal/test/models"
 )
 
@@ -129,3 +136,126 @@ func (s *ReformSuite) TestWriteReadInTransactionOnMaster() {
 	}
 	tx.Rollback()
 }
+
+func TestDefaultRoutingPolicy(t *testing.T) {
+	t.Parallel()
+
+	for query, expected := range map[string]reform.Route{
+		"SELECT * FROM people":   reform.RouteReplica,
+		"  select * from people": reform.RouteReplica,
+		"SELECT /* test:TestRouting */ * FROM people WHERE id = $1": reform.RouteReplica,
+		"-- comment\nSELECT 1":                             reform.RouteReplica,
+		"/* comment */ SELECT 1":                           reform.RouteReplica,
+		"(SELECT 1) UNION (SELECT 2)":                      reform.RouteReplica,
+		"WITH p AS (SELECT * FROM people) SELECT * FROM p": reform.RouteReplica,
+		"SELECT * FROM people WHERE name = 'for update'":   reform.RouteReplica,
+		`SELECT "update", [delete] FROM people`:            reform.RouteReplica,
+		"SELECT $$ nextval( $$, $tag$ into $tag$":          reform.RouteReplica,
+		"SELECT updated_at, nextval FROM people;":          reform.RouteReplica,
+
+		"INSERT INTO people (name) VALUES ($1)":                      reform.RouteMaster,
+		"UPDATE people SET name = 'SELECT'":                          reform.RouteMaster,
+		"/* SELECT */ DELETE FROM people":                            reform.RouteMaster,
+		"SELECT * FROM people FOR UPDATE":                            reform.RouteMaster,
+		"SELECT * FROM people FOR NO KEY UPDATE":                     reform.RouteMaster,
+		"select * from people for share":                             reform.RouteMaster,
+		"SELECT * FROM people FOR KEY SHARE":                         reform.RouteMaster,
+		"SELECT * FROM people LOCK IN SHARE MODE":                    reform.RouteMaster,
+		"SELECT * FROM people WITH (UPDLOCK)":                        reform.RouteMaster,
+		"SELECT nextval('people_id_seq')":                            reform.RouteMaster,
+		"SELECT pg_catalog.NEXTVAL ('people_id_seq')":                reform.RouteMaster,
+		"SELECT LAST_INSERT_ID()":                                    reform.RouteMaster,
+		"SELECT NEXT VALUE FOR people_seq":                           reform.RouteMaster,
+		"SELECT * INTO people_copy FROM people":                      reform.RouteMaster,
+		"WITH d AS (DELETE FROM people RETURNING *) SELECT * FROM d": reform.RouteMaster,
+		"SELECT 1; DELETE FROM people":                               reform.RouteMaster,
+		"EXPLAIN ANALYZE SELECT 1":                                   reform.RouteMaster,
+		"":                                                           reform.RouteMaster,
+	} {
+		assert.Equal(t, expected, reform.DefaultRoutingPolicy{}.Route(query), "%s", query)
+	}
+
+	p := reform.DefaultRoutingPolicy{VolatileFunctions: []string{"audit_read"}}
+	assert.Equal(t, reform.RouteMaster, p.Route("SELECT Audit_Read(id) FROM people"))
+	assert.Equal(t, reform.RouteReplica, p.Route("SELECT audit_read FROM people"))
+}
+
+func TestRouting(t *testing.T) {
+	db := setupDB(t)
+	defer teardown(t, db)
+
+	// slave is a separate connection pool to the same database without idle connections,
+	// so each query sent to it closes a connection, which is visible in pool's statistics
+	slaveDB := test.ConnectToTestDB()
+	defer teardown(t, slaveDB)
+	slave := slaveDB.DBInterface().(*sql.DB)
+	slave.SetMaxIdleConns(0)
+	db.AddSlaves(slave)
+
+	onSlave := func(t *testing.T, q *reform.Querier, query string) bool {
+		t.Helper()
+
+		before := slave.Stats().MaxIdleClosed
+		rows, err := q.Query(query)
+		require.NoError(t, err)
+		require.NoError(t, rows.Close())
+		return slave.Stats().MaxIdleClosed > before
+	}
+
+	t.Run("Default", func(t *testing.T) {
+		assert.True(t, onSlave(t, db.Querier, "SELECT 1"))
+		assert.True(t, onSlave(t, db.Querier, "select 1"))
+		assert.True(t, onSlave(t, db.Querier, "SELECT ABS(1)"))
+	})
+
+	t.Run("Policy", func(t *testing.T) {
+		db.SetRoutingPolicy(reform.DefaultRoutingPolicy{VolatileFunctions: []string{"abs"}})
+		defer db.SetRoutingPolicy(nil)
+
+		assert.True(t, onSlave(t, db.Querier, "SELECT 1"))
+		assert.False(t, onSlave(t, db.Querier, "SELECT ABS(1)"))
+		assert.True(t, onSlave(t, db.OnReplica(), "SELECT ABS(1)"))
+		assert.False(t, onSlave(t, db.OnMaster(), "SELECT 1"))
+		assert.True(t, onSlave(t, db.OnMaster().OnReplica(), "SELECT ABS(1)"))
+		assert.False(t, onSlave(t, db.OnReplica().WithTag("tag").OnMaster(), "SELECT 1"))
+
+		db.SetRoutingPolicy(reform.RoutingPolicyFunc(func(string) reform.Route { return reform.RouteMaster }))
+		assert.False(t, onSlave(t, db.Querier, "SELECT 1"))
+	})
+
+	t.Run("ReadYourWrites", func(t *testing.T) {
+		ctx := reform.WithReadYourWrites(context.Background(), time.Hour)
+		q := db.WithContext(ctx)
+		assert.True(t, onSlave(t, q, "SELECT 1"))
+
+		// write in transaction started with that context
+		tx, err := db.BeginTx(ctx, nil)
+		require.NoError(t, err)
+		_, err = tx.Exec("DELETE FROM people WHERE id = -1")
+		require.NoError(t, err)
+		require.NoError(t, tx.Rollback())
+
+		assert.False(t, onSlave(t, q, "SELECT 1"))
+		assert.False(t, onSlave(t, db.WithContext(context.WithValue(ctx, ctxKey("k"), "v")), "SELECT 1"))
+		assert.True(t, onSlave(t, q.OnReplica(), "SELECT 1"))
+		assert.True(t, onSlave(t, db.Querier, "SELECT 1"), "other contexts are not affected")
+
+		ctx = reform.WithReadYourWrites(context.Background(), time.Millisecond)
+		q = db.WithContext(ctx)
+		_, err = q.Exec("DELETE FROM people WHERE id = -1")
+		require.NoError(t, err)
+		time.Sleep(10 * time.Millisecond)
+		assert.True(t, onSlave(t, q, "SELECT 1"), "window expired")
+	})
+
+	t.Run("Transaction", func(t *testing.T) {
+		tx, err := db.Begin()
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, tx.Rollback())
+		}()
+
+		assert.False(t, onSlave(t, tx.Querier, "SELECT 1"))
+		assert.False(t, onSlave(t, tx.OnReplica(), "SELECT 1"))
+	})
+}
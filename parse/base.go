@@ -13,9 +13,14 @@ import (
 
 // FieldInfo represents information about struct field.
 type FieldInfo struct {
-	Name   string // field name as defined in source file, e.g. Name
-	Type   string // field type as defined in source file, e.g. string; always present for primary key, may be absent otherwise
-	Column string // SQL database column name from "reform:" struct field tag, e.g. name
+	Name       string // field name as defined in source file, e.g. Name
+	Type       string // field type as defined in source file, e.g. string; always present for primary key, may be absent otherwise
+	Column     string // SQL database column name from "reform:" struct field tag, e.g. name
+	Version    bool   // true for version field used for optimistic locking ("version" label in "reform:" struct field tag)
+	SoftDelete bool   // true for soft delete timestamp field ("softdelete" label in "reform:" struct field tag)
+	AutoCreate bool   // true for timestamp field set on insert ("autocreate" label in "reform:" struct field tag)
+	AutoUpdate bool   // true for timestamp field set on update ("autoupdate" label in "reform:" struct field tag)
+	FK         string // referenced table and column for foreign key field ("fk=table.column" label in "reform:" struct field tag), e.g. groups.id
 }
 
 // fieldInfoInSync returns true if FieldInfo fields that are set by both file and runtime parser are equal.
@@ -29,12 +34,49 @@ func fieldInfoInSync(fi1, fi2 *FieldInfo) bool {
 
 	return fi1.Name == fi2.Name &&
 		fi1.Type == fi2.Type &&
-		fi1.Column == fi2.Column
+		fi1.Column == fi2.Column &&
+		fi1.Version == fi2.Version &&
+		fi1.SoftDelete == fi2.SoftDelete &&
+		fi1.AutoCreate == fi2.AutoCreate &&
+		fi1.AutoUpdate == fi2.AutoUpdate &&
+		fi1.FK == fi2.FK
 }
 
 // GoString returns struct field information as Go code string.
 func (fi *FieldInfo) GoString() string {
-	return fmt.Sprintf("{Name: %q, Type: %q, Column: %q}", fi.Name, fi.Type, fi.Column)
+	res := fmt.Sprintf("{Name: %q, Type: %q, Column: %q", fi.Name, fi.Type, fi.Column)
+	if fi.Version {
+		res += ", Version: true"
+	}
+	if fi.SoftDelete {
+		res += ", SoftDelete: true"
+	}
+	if fi.AutoCreate {
+		res += ", AutoCreate: true"
+	}
+	if fi.AutoUpdate {
+		res += ", AutoUpdate: true"
+	}
+	if fi.FK != "" {
+		res += fmt.Sprintf(", FK: %q", fi.FK)
+	}
+	return res + "}"
+}
+
+// FKTable returns referenced table name (possibly qualified with schema) for foreign key field, empty string otherwise.
+func (fi *FieldInfo) FKTable() string {
+	if i := strings.LastIndex(fi.FK, "."); i > 0 {
+		return fi.FK[:i]
+	}
+	return ""
+}
+
+// FKColumn returns referenced column name for foreign key field, empty string otherwise.
+func (fi *FieldInfo) FKColumn() string {
+	if i := strings.LastIndex(fi.FK, "."); i > 0 {
+		return fi.FK[i+1:]
+	}
+	return ""
 }
 
 // StructInfo represents information about struct.
@@ -43,7 +85,9 @@ type StructInfo struct {
 	SQLSchema    string      // SQL database schema name from magic "reform:" comment, e.g. public
 	SQLName      string      // SQL database view or table name from magic "reform:" comment, e.g. users
 	Fields       []FieldInfo // fields info
-	PKFieldIndex int         // index of primary key field in Fields, -1 if none
+	PKFieldIndex int         // index of primary key field in Fields, -1 if none; index of the first one for composite primary key
+
+	PKFieldIndexes []int // indexes of all primary key fields in Fields for composite primary key, nil otherwise
 }
 
 // structInfoInSync returns true if FieldInfo fields that are set by both file and runtime parser are equal.
@@ -63,6 +107,15 @@ func structInfoInSync(si1, si2 *StructInfo) bool {
 		return false
 	}
 
+	if len(si1.PKFieldIndexes) != len(si2.PKFieldIndexes) {
+		return false
+	}
+	for i := range si1.PKFieldIndexes {
+		if si1.PKFieldIndexes[i] != si2.PKFieldIndexes[i] {
+			return false
+		}
+	}
+
 	if len(si1.Fields) != len(si2.Fields) {
 		return false
 	}
@@ -91,6 +144,9 @@ func (s *StructInfo) GoString() string {
 	res += "\t},\n"
 
 	res += fmt.Sprintf("\tPKFieldIndex: %d,\n", s.PKFieldIndex)
+	if s.PKFieldIndexes != nil {
+		res += fmt.Sprintf("\tPKFieldIndexes: %#v,\n", s.PKFieldIndexes)
+	}
 
 	res += "}"
 	return res
@@ -119,7 +175,12 @@ func (s *StructInfo) IsTable() bool {
 	return s.PKFieldIndex >= 0
 }
 
-// PKField returns a primary key field, panics for views.
+// IsCompositePK returns true if this object represent information for table with composite primary key.
+func (s *StructInfo) IsCompositePK() bool {
+	return len(s.PKFieldIndexes) > 1
+}
+
+// PKField returns a primary key field (the first one for composite primary key), panics for views.
 func (s *StructInfo) PKField() FieldInfo {
 	if !s.IsTable() {
 		panic("reform: not a table")
@@ -127,6 +188,84 @@ func (s *StructInfo) PKField() FieldInfo {
 	return s.Fields[s.PKFieldIndex]
 }
 
+// PKFields returns all primary key fields, panics for views.
+func (s *StructInfo) PKFields() []FieldInfo {
+	if !s.IsTable() {
+		panic("reform: not a table")
+	}
+	if !s.IsCompositePK() {
+		return []FieldInfo{s.Fields[s.PKFieldIndex]}
+	}
+	res := make([]FieldInfo, len(s.PKFieldIndexes))
+	for i, pk := range s.PKFieldIndexes {
+		res[i] = s.Fields[pk]
+	}
+	return res
+}
+
+// FKFields returns all foreign key fields.
+func (s *StructInfo) FKFields() []FieldInfo {
+	var res []FieldInfo
+	for _, f := range s.Fields {
+		if f.FK != "" {
+			res = append(res, f)
+		}
+	}
+	return res
+}
+
+// VersionFieldIndex returns an index of version field in Fields, -1 if none.
+func (s *StructInfo) VersionFieldIndex() int {
+	for i, f := range s.Fields {
+		if f.Version {
+			return i
+		}
+	}
+	return -1
+}
+
+// SoftDeleteFieldIndex returns an index of soft delete field in Fields, -1 if none.
+func (s *StructInfo) SoftDeleteFieldIndex() int {
+	for i, f := range s.Fields {
+		if f.SoftDelete {
+			return i
+		}
+	}
+	return -1
+}
+
+// AutoCreateFieldIndex returns an index of timestamp field set on insert in Fields, -1 if none.
+func (s *StructInfo) AutoCreateFieldIndex() int {
+	for i, f := range s.Fields {
+		if f.AutoCreate {
+			return i
+		}
+	}
+	return -1
+}
+
+// AutoUpdateFieldIndex returns an index of timestamp field set on update in Fields, -1 if none.
+func (s *StructInfo) AutoUpdateFieldIndex() int {
+	for i, f := range s.Fields {
+		if f.AutoUpdate {
+			return i
+		}
+	}
+	return -1
+}
+
+// addPKFieldIndex records field with given index as primary key field.
+func (s *StructInfo) addPKFieldIndex(i int) {
+	if s.PKFieldIndex < 0 {
+		s.PKFieldIndex = i
+		return
+	}
+	if s.PKFieldIndexes == nil {
+		s.PKFieldIndexes = []int{s.PKFieldIndex}
+	}
+	s.PKFieldIndexes = append(s.PKFieldIndexes, i)
+}
+
 // AssertUpToDate checks that given StructInfo matches given object.
 // It is used during program initialization to check that generated files are up-to-date.
 func AssertUpToDate(si *StructInfo, obj interface{}) {
@@ -144,26 +283,106 @@ func AssertUpToDate(si *StructInfo, obj interface{}) {
 	}
 }
 
-// parseStructFieldTag is used by both file and runtime parsers
-func parseStructFieldTag(tag string) (sqlName string, isPK bool) {
+// fieldTag represents parsed "reform:" struct field tag.
+type fieldTag struct {
+	column       string // empty for invalid tag value
+	isPK         bool
+	isVersion    bool
+	isSoftDelete bool
+	isAutoCreate bool
+	isAutoUpdate bool
+	fk           string
+}
+
+// parseStructFieldTag is used by both file and runtime parsers.
+// Column name may be followed by at most one of "pk", "version", "softdelete", "autocreate", "autoupdate" labels,
+// and "fk=table.column" label, which may be combined only with "pk". Labels may go in any order.
+func parseStructFieldTag(tag string) (res fieldTag) {
 	parts := strings.Split(tag, ",")
-	if len(parts) == 0 || len(parts) > 2 {
+	if len(parts) == 0 {
 		return
 	}
 
-	if len(parts) == 2 {
-		switch parts[1] {
+	var labels int // the number of labels other than "fk="
+	for _, label := range parts[1:] {
+		switch label {
 		case "pk":
-			isPK = true
+			if res.isPK {
+				return
+			}
+			res.isPK = true
+		case "version":
+			if res.isVersion {
+				return
+			}
+			res.isVersion = true
+		case "softdelete":
+			if res.isSoftDelete {
+				return
+			}
+			res.isSoftDelete = true
+		case "autocreate":
+			if res.isAutoCreate {
+				return
+			}
+			res.isAutoCreate = true
+		case "autoupdate":
+			if res.isAutoUpdate {
+				return
+			}
+			res.isAutoUpdate = true
 		default:
-			return
+			fk := strings.TrimPrefix(label, "fk=")
+			if i := strings.LastIndex(fk, "."); fk == label || i <= 0 || i == len(fk)-1 || res.fk != "" {
+				return
+			}
+			res.fk = fk
+			continue
 		}
+		labels++
+	}
+
+	// at most one label, and only "pk" can be combined with "fk="
+	if labels > 1 || (res.fk != "" && labels == 1 && !res.isPK) {
+		res = fieldTag{}
+		return
 	}
 
-	sqlName = parts[0]
+	res.column = parts[0]
 	return
 }
 
+// labeledField describes "reform:" struct field tag label that can be used by at most one table field.
+type labeledField struct {
+	label string
+	is    func(f *FieldInfo) bool
+	types map[string]struct{}
+}
+
+//nolint:gochecknoglobals
+var (
+	labeledFields = []labeledField{{
+		label: "version",
+		is:    func(f *FieldInfo) bool { return f.Version },
+		types: map[string]struct{}{
+			"int": {}, "int8": {}, "int16": {}, "int32": {}, "int64": {},
+			"uint": {}, "uint8": {}, "uint16": {}, "uint32": {}, "uint64": {},
+		},
+	}, {
+		label: "softdelete",
+		is:    func(f *FieldInfo) bool { return f.SoftDelete },
+		types: map[string]struct{}{"*time.Time": {}},
+	}, {
+		label: "autocreate",
+		is:    func(f *FieldInfo) bool { return f.AutoCreate },
+		types: map[string]struct{}{"time.Time": {}, "*time.Time": {}},
+	}, {
+		label: "autoupdate",
+		is:    func(f *FieldInfo) bool { return f.AutoUpdate },
+		types: map[string]struct{}{"time.Time": {}, "*time.Time": {}},
+	}}
+)
+
 // checkFields is used by both file and runtime parsers
 func checkFields(res *StructInfo) error {
 	if len(res.Fields) == 0 {
@@ -179,5 +398,28 @@ func checkFields(res *StructInfo) error {
 		dupes[f.Column] = f.Name
 	}
 
+	for _, lf := range labeledFields {
+		var used string
+		for i := range res.Fields {
+			f := &res.Fields[i]
+			if !lf.is(f) {
+				continue
+			}
+			if _, ok := lf.types[f.Type]; !ok {
+				return fmt.Errorf(`reform: %s has field %s of type %s with %q label in "reform:" tag, it is not allowed`,
+					res.Type, f.Name, f.Type, lf.label)
+			}
+			if used != "" {
+				return fmt.Errorf(`reform: %s has field %s with %q label in "reform:" tag (used by %s), it is not allowed`,
+					res.Type, f.Name, lf.label, used)
+			}
+			if !res.IsTable() {
+				return fmt.Errorf(`reform: %s has field %s with %q label in "reform:" tag without primary key, it is not allowed`,
+					res.Type, f.Name, lf.label)
+			}
+			used = f.Name
+		}
+	}
+
 	return nil
 }
@@ -81,7 +81,8 @@ func parseStructTypeSpec(ts *ast.TypeSpec, str *ast.StructType) (*StructInfo, er
 		}
 
 		// parse tag and type
-		column, isPK := parseStructFieldTag(tag)
+		ft := parseStructFieldTag(tag)
+		column, isPK := ft.column, ft.isPK
 		if column == "" {
 			return nil, fmt.Errorf(`reform: %s has field %s with invalid "reform:" tag value, it is not allowed`, res.Type, name.Name)
 		}
@@ -93,18 +94,20 @@ func parseStructTypeSpec(ts *ast.TypeSpec, str *ast.StructType) (*StructInfo, er
 			if strings.HasPrefix(typ, "[") {
 				return nil, fmt.Errorf(`reform: %s has slice field %s with with "pk" label in "reform:" tag, it is not allowed`, res.Type, name.Name)
 			}
-			if res.PKFieldIndex >= 0 {
-				return nil, fmt.Errorf(`reform: %s has field %s with with duplicate "pk" label in "reform:" tag (first used by %s), it is not allowed`, res.Type, name.Name, res.Fields[res.PKFieldIndex].Name)
-			}
 		}
 
 		res.Fields = append(res.Fields, FieldInfo{
-			Name:   name.Name,
-			Type:   typ,
-			Column: column,
+			Name:       name.Name,
+			Type:       typ,
+			Column:     column,
+			Version:    ft.isVersion,
+			SoftDelete: ft.isSoftDelete,
+			AutoCreate: ft.isAutoCreate,
+			AutoUpdate: ft.isAutoUpdate,
+			FK:         ft.fk,
 		})
 		if isPK {
-			res.PKFieldIndex = n
+			res.addPKFieldIndex(n)
 		}
 		n++
 	}
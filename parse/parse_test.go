@@ -46,8 +46,8 @@ var (
 		Type:    "PersonProject",
 		SQLName: "person_project",
 		Fields: []FieldInfo{
-			{Name: "PersonID", Type: "int32", Column: "person_id"},
-			{Name: "ProjectID", Type: "string", Column: "project_id"},
+			{Name: "PersonID", Type: "int32", Column: "person_id", FK: "people.id"},
+			{Name: "ProjectID", Type: "string", Column: "project_id", FK: "projects.id"},
 		},
 		PKFieldIndex: -1,
 	}
@@ -82,6 +82,32 @@ var (
 		PKFieldIndex: 0,
 	}
 
+	compositePK = StructInfo{
+		Type:    "CompositePK",
+		SQLName: "composite_pk",
+		Fields: []FieldInfo{
+			{Name: "I", Type: "int32", Column: "i"},
+			{Name: "Name", Type: "string", Column: "name"},
+			{Name: "ID", Type: "string", Column: "id"},
+		},
+		PKFieldIndex:   0,
+		PKFieldIndexes: []int{0, 2},
+	}
+
+	document = StructInfo{
+		Type:    "Document",
+		SQLName: "documents",
+		Fields: []FieldInfo{
+			{Name: "ID", Type: "int32", Column: "id"},
+			{Name: "Name", Type: "string", Column: "name"},
+			{Name: "LockVersion", Type: "int64", Column: "lock_version", Version: true},
+			{Name: "DeletedAt", Type: "*time.Time", Column: "deleted_at", SoftDelete: true},
+			{Name: "CreatedAt", Type: "time.Time", Column: "created_at", AutoCreate: true},
+			{Name: "UpdatedAt", Type: "*time.Time", Column: "updated_at", AutoUpdate: true},
+		},
+		PKFieldIndex: 0,
+	}
+
 	extra = StructInfo{
 		Type:    "Extra",
 		SQLName: "extra",
@@ -102,6 +128,17 @@ var (
 		PKFieldIndex: 0,
 	}
 
+	extraPersonProject = StructInfo{
+		Type:    "ExtraPersonProject",
+		SQLName: "extra_person_project",
+		Fields: []FieldInfo{
+			{Name: "PersonID", Type: "int32", Column: "person_id", FK: "people.id"},
+			{Name: "ProjectID", Type: "string", Column: "project_id", FK: "projects.id"},
+		},
+		PKFieldIndex:   0,
+		PKFieldIndexes: []int{0, 1},
+	}
+
 	notExported = StructInfo{
 		Type:    "notExported",
 		SQLName: "not_exported",
@@ -115,21 +152,24 @@ var (
 func TestFileGood(t *testing.T) {
 	s, err := File(filepath.FromSlash("../internal/test/models/good.go"))
 	assert.NoError(t, err)
-	require.Len(t, s, 6)
+	require.Len(t, s, 8)
 	assert.Equal(t, person, s[0])
 	assert.Equal(t, project, s[1])
 	assert.Equal(t, personProject, s[2])
 	assert.Equal(t, idOnly, s[3])
 	assert.Equal(t, constraints, s[4])
 	assert.Equal(t, legacyPerson, s[5])
+	assert.Equal(t, compositePK, s[6])
+	assert.Equal(t, document, s[7])
 }
 
 func TestFileExtra(t *testing.T) {
 	s, err := File(filepath.FromSlash("../internal/test/models/extra.go"))
 	assert.NoError(t, err)
-	require.Len(t, s, 2)
+	require.Len(t, s, 3)
 	assert.Equal(t, extra, s[0])
-	assert.Equal(t, notExported, s[1])
+	assert.Equal(t, extraPersonProject, s[1])
+	assert.Equal(t, notExported, s[2])
 }
 
 func TestFileBogus(t *testing.T) {
@@ -145,8 +185,19 @@ func TestFileBogus(t *testing.T) {
 		// "bogus8.go": errors.New(`reform: Bogus8 has pointer field Bogus with with "omitempty" label in "reform:" tag, it is not allowed`),
 		"bogus8.go":  errors.New(`reform: Bogus8 has field Bogus with invalid "reform:" tag value, it is not allowed`),
 		"bogus9.go":  errors.New(`reform: Bogus9 has field Bogus2 with "reform:" tag with duplicate column name bogus (used by Bogus1), it is not allowed`),
-		"bogus10.go": errors.New(`reform: Bogus10 has field Bogus2 with with duplicate "pk" label in "reform:" tag (first used by Bogus1), it is not allowed`),
+		"bogus10.go": errors.New(`reform: Bogus10 has pointer field Bogus2 with with "pk" label in "reform:" tag, it is not allowed`),
 		"bogus11.go": errors.New(`reform: Bogus11 has slice field Bogus with with "pk" label in "reform:" tag, it is not allowed`),
+		"bogus12.go": errors.New(`reform: Bogus12 has field Bogus of type string with "version" label in "reform:" tag, it is not allowed`),
+		"bogus13.go": errors.New(`reform: Bogus13 has field Bogus2 with "version" label in "reform:" tag (used by Bogus1), it is not allowed`),
+		"bogus14.go": errors.New(`reform: Bogus14 has field Bogus with "version" label in "reform:" tag without primary key, it is not allowed`),
+		"bogus15.go": errors.New(`reform: Bogus15 has field Bogus of type time.Time with "softdelete" label in "reform:" tag, it is not allowed`),
+		"bogus16.go": errors.New(`reform: Bogus16 has field Bogus of type string with "autoupdate" label in "reform:" tag, it is not allowed`),
+		"bogus17.go": errors.New(`reform: Bogus17 has field Bogus with invalid "reform:" tag value, it is not allowed`),
+		"bogus18.go": errors.New(`reform: Bogus18 has field ID with invalid "reform:" tag value, it is not allowed`),
+		"bogus19.go": errors.New(`reform: Bogus19 has field Bogus with invalid "reform:" tag value, it is not allowed`),
+		"bogus20.go": errors.New(`reform: Bogus20 has field Bogus with invalid "reform:" tag value, it is not allowed`),
+		"bogus21.go": errors.New(`reform: Bogus21 has field Bogus with invalid "reform:" tag value, it is not allowed`),
+		"bogus22.go": errors.New(`reform: Bogus22 has field ID with invalid "reform:" tag value, it is not allowed`),
 
 		"bogus_ignore.go": nil,
 	} {
@@ -180,6 +231,14 @@ func TestObjectGood(t *testing.T) {
 	s, err = Object(new(models.LegacyPerson), "legacy", "people")
 	assert.NoError(t, err)
 	assert.Equal(t, &legacyPerson, s)
+
+	s, err = Object(new(models.CompositePK), "", "composite_pk")
+	assert.NoError(t, err)
+	assert.Equal(t, &compositePK, s)
+
+	s, err = Object(new(models.Document), "", "documents")
+	assert.NoError(t, err)
+	assert.Equal(t, &document, s)
 }
 
 func TestObjectExtra(t *testing.T) {
@@ -187,6 +246,10 @@ func TestObjectExtra(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, &extra, s)
 
+	s, err = Object(new(models.ExtraPersonProject), "", "extra_person_project")
+	assert.NoError(t, err)
+	assert.Equal(t, &extraPersonProject, s)
+
 	// s, err := Object(new(models.notExported), "", "not_exported")
 	// assert.NoError(t, err)
 	// assert.Equal(t, &notExported, s)
@@ -204,8 +267,19 @@ func TestObjectBogus(t *testing.T) {
 		// new(bogus.Bogus8): errors.New(`reform: Bogus8 has pointer field Bogus with with "omitempty" label in "reform:" tag, it is not allowed`),
 		new(bogus.Bogus8):  errors.New(`reform: Bogus8 has field Bogus with invalid "reform:" tag value, it is not allowed`),
 		new(bogus.Bogus9):  errors.New(`reform: Bogus9 has field Bogus2 with "reform:" tag with duplicate column name bogus (used by Bogus1), it is not allowed`),
-		new(bogus.Bogus10): errors.New(`reform: Bogus10 has field Bogus2 with with duplicate "pk" label in "reform:" tag (first used by Bogus1), it is not allowed`),
+		new(bogus.Bogus10): errors.New(`reform: Bogus10 has pointer field Bogus2 with with "pk" label in "reform:" tag, it is not allowed`),
 		new(bogus.Bogus11): errors.New(`reform: Bogus11 has slice field Bogus with with "pk" label in "reform:" tag, it is not allowed`),
+		new(bogus.Bogus12): errors.New(`reform: Bogus12 has field Bogus of type string with "version" label in "reform:" tag, it is not allowed`),
+		new(bogus.Bogus13): errors.New(`reform: Bogus13 has field Bogus2 with "version" label in "reform:" tag (used by Bogus1), it is not allowed`),
+		new(bogus.Bogus14): errors.New(`reform: Bogus14 has field Bogus with "version" label in "reform:" tag without primary key, it is not allowed`),
+		new(bogus.Bogus15): errors.New(`reform: Bogus15 has field Bogus of type time.Time with "softdelete" label in "reform:" tag, it is not allowed`),
+		new(bogus.Bogus16): errors.New(`reform: Bogus16 has field Bogus of type string with "autoupdate" label in "reform:" tag, it is not allowed`),
+		new(bogus.Bogus17): errors.New(`reform: Bogus17 has field Bogus with invalid "reform:" tag value, it is not allowed`),
+		new(bogus.Bogus18): errors.New(`reform: Bogus18 has field ID with invalid "reform:" tag value, it is not allowed`),
+		new(bogus.Bogus19): errors.New(`reform: Bogus19 has field Bogus with invalid "reform:" tag value, it is not allowed`),
+		new(bogus.Bogus20): errors.New(`reform: Bogus20 has field Bogus with invalid "reform:" tag value, it is not allowed`),
+		new(bogus.Bogus21): errors.New(`reform: Bogus21 has field Bogus with invalid "reform:" tag value, it is not allowed`),
+		new(bogus.Bogus22): errors.New(`reform: Bogus22 has field ID with invalid "reform:" tag value, it is not allowed`),
 
 		// new(bogus.BogusIgnore): do not test,
 	} {
@@ -276,8 +350,8 @@ parse.StructInfo{
 	Type: "PersonProject",
 	SQLName: "person_project",
 	Fields: []parse.FieldInfo{
-		{Name: "PersonID", Type: "int32", Column: "person_id"},
-		{Name: "ProjectID", Type: "string", Column: "project_id"},
+		{Name: "PersonID", Type: "int32", Column: "person_id", FK: "people.id"},
+		{Name: "ProjectID", Type: "string", Column: "project_id", FK: "projects.id"},
 	},
 	PKFieldIndex: -1,
 }`), personProject.GoString())
@@ -288,6 +362,9 @@ parse.StructInfo{
 	"project_id",
 }`), personProject.ColumnsGoString())
 		assert.False(t, personProject.IsTable())
+		assert.Equal(t, personProject.Fields, personProject.FKFields())
+		assert.Equal(t, "projects", personProject.Fields[1].FKTable())
+		assert.Equal(t, "id", personProject.Fields[1].FKColumn())
 	})
 
 	t.Run("constraints", func(t *testing.T) {
@@ -351,6 +428,52 @@ parse.StructInfo{
 		assert.True(t, legacyPerson.IsTable())
 		assert.Equal(t, FieldInfo{Name: "ID", Type: "int32", Column: "id"}, legacyPerson.PKField())
 	})
+
+	t.Run("compositePK", func(t *testing.T) {
+		assert.Equal(t, strings.TrimSpace(`
+parse.StructInfo{
+	Type: "CompositePK",
+	SQLName: "composite_pk",
+	Fields: []parse.FieldInfo{
+		{Name: "I", Type: "int32", Column: "i"},
+		{Name: "Name", Type: "string", Column: "name"},
+		{Name: "ID", Type: "string", Column: "id"},
+	},
+	PKFieldIndex: 0,
+	PKFieldIndexes: []int{0, 2},
+}`), compositePK.GoString())
+		assert.Equal(t, []string{"i", "name", "id"}, compositePK.Columns())
+		assert.True(t, compositePK.IsTable())
+		assert.True(t, compositePK.IsCompositePK())
+		assert.Equal(t, FieldInfo{Name: "I", Type: "int32", Column: "i"}, compositePK.PKField())
+		assert.Equal(t, []FieldInfo{
+			{Name: "I", Type: "int32", Column: "i"},
+			{Name: "ID", Type: "string", Column: "id"},
+		}, compositePK.PKFields())
+		assert.Equal(t, -1, compositePK.VersionFieldIndex())
+	})
+
+	t.Run("document", func(t *testing.T) {
+		assert.Equal(t, strings.TrimSpace(`
+parse.StructInfo{
+	Type: "Document",
+	SQLName: "documents",
+	Fields: []parse.FieldInfo{
+		{Name: "ID", Type: "int32", Column: "id"},
+		{Name: "Name", Type: "string", Column: "name"},
+		{Name: "LockVersion", Type: "int64", Column: "lock_version", Version: true},
+		{Name: "DeletedAt", Type: "*time.Time", Column: "deleted_at", SoftDelete: true},
+		{Name: "CreatedAt", Type: "time.Time", Column: "created_at", AutoCreate: true},
+		{Name: "UpdatedAt", Type: "*time.Time", Column: "updated_at", AutoUpdate: true},
+	},
+	PKFieldIndex: 0,
+}`), document.GoString())
+		assert.True(t, document.IsTable())
+		assert.Equal(t, 2, document.VersionFieldIndex())
+		assert.Equal(t, 3, document.SoftDeleteFieldIndex())
+		assert.Equal(t, 4, document.AutoCreateFieldIndex())
+		assert.Equal(t, 5, document.AutoUpdateFieldIndex())
+	})
 }
 
 func TestHelpersExtra(t *testing.T) {
@@ -437,4 +560,14 @@ func TestAssertUpToDate(t *testing.T) {
 		p.PKFieldIndex = 1
 		AssertUpToDate(&p, new(models.Person))
 	}()
+
+	func() {
+		defer func() {
+			assert.NotNil(t, recover())
+		}()
+
+		p := compositePK
+		p.PKFieldIndexes = []int{0, 1}
+		AssertUpToDate(&p, new(models.CompositePK))
+	}()
 }
@@ -63,7 +63,8 @@ func Object(obj interface{}, schema, table string) (res *StructInfo, err error)
 		}
 
 		// parse tag and type
-		column, isPK := parseStructFieldTag(tag)
+		ft := parseStructFieldTag(tag)
+		column, isPK := ft.column, ft.isPK
 		if column == "" {
 			return nil, fmt.Errorf(`reform: %s has field %s with invalid "reform:" tag value, it is not allowed`, res.Type, f.Name)
 		}
@@ -75,18 +76,20 @@ func Object(obj interface{}, schema, table string) (res *StructInfo, err error)
 			if strings.HasPrefix(typ, "[") {
 				return nil, fmt.Errorf(`reform: %s has slice field %s with with "pk" label in "reform:" tag, it is not allowed`, res.Type, f.Name)
 			}
-			if res.PKFieldIndex >= 0 {
-				return nil, fmt.Errorf(`reform: %s has field %s with with duplicate "pk" label in "reform:" tag (first used by %s), it is not allowed`, res.Type, f.Name, res.Fields[res.PKFieldIndex].Name)
-			}
 		}
 
 		res.Fields = append(res.Fields, FieldInfo{
-			Name:   f.Name,
-			Type:   typ,
-			Column: column,
+			Name:       f.Name,
+			Type:       typ,
+			Column:     column,
+			Version:    ft.isVersion,
+			SoftDelete: ft.isSoftDelete,
+			AutoCreate: ft.isAutoCreate,
+			AutoUpdate: ft.isAutoUpdate,
+			FK:         ft.fk,
 		})
 		if isPK {
-			res.PKFieldIndex = n
+			res.addPKFieldIndex(n)
 		}
 		n++
 	}
@@ -3,9 +3,8 @@ package reform
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
-	"math/rand"
-	"strings"
 	"time"
 )
 
@@ -17,8 +16,17 @@ type Querier struct {
 	Dialect
 	Logger        Logger
 	inTransaction bool
-	slaves        []DBTXContext
+	replicas      *ReplicaPool
 	onCommitCalls []func() error
+	withDeleted   bool
+	clock         func() time.Time
+	timePrecision time.Duration
+	routing       RoutingPolicy
+	route         Route
+	replica       string
+	pinned        *replica // replica chosen by QuerierForRead
+	interceptors  []Interceptor
+	autoIncrement *autoIncrementMode // shared by DB and its master transactions
 }
 
 func newQuerier(
@@ -28,7 +36,7 @@ func newQuerier(
 	dialect Dialect,
 	logger Logger,
 	inTransaction bool,
-	slaves []DBTXContext,
+	replicas *ReplicaPool,
 	onCommitCalls []func() error,
 ) *Querier {
 	return &Querier{
@@ -38,13 +46,37 @@ func newQuerier(
 		Dialect:       dialect,
 		Logger:        logger,
 		inTransaction: inTransaction,
-		slaves:        slaves,
+		replicas:      replicas,
 		onCommitCalls: onCommitCalls,
 	}
 }
 
 func (q *Querier) clone() *Querier {
-	return newQuerier(q.ctx, q.dbtxCtx, q.tag, q.Dialect, q.Logger, q.inTransaction, q.slaves, q.onCommitCalls)
+	newQ := newQuerier(q.ctx, q.dbtxCtx, q.tag, q.Dialect, q.Logger, q.inTransaction, q.replicas, q.onCommitCalls)
+	newQ.withDeleted = q.withDeleted
+	newQ.clock = q.clock
+	newQ.timePrecision = q.timePrecision
+	newQ.routing = q.routing
+	newQ.route = q.route
+	newQ.replica = q.replica
+	newQ.pinned = q.pinned
+	newQ.interceptors = q.interceptors
+	newQ.autoIncrement = q.autoIncrement
+	return newQ
+}
+
+// now returns the current time in UTC for autocreate, autoupdate and soft delete columns,
+// truncated to configured precision.
+func (q *Querier) now() time.Time {
+	clock := q.clock
+	if clock == nil {
+		clock = time.Now
+	}
+	t := clock().UTC() // also strips monotonic clock reading
+	if q.timePrecision > 0 {
+		t = t.Truncate(q.timePrecision)
+	}
+	return t
 }
 
 func (q *Querier) logBefore(query string, args []interface{}) {
@@ -83,6 +115,50 @@ func (q *Querier) WithTag(format string, args ...interface{}) *Querier {
 	return newQ
 }
 
+// WithDeleted returns a copy of Querier which selectors do not exclude soft-deleted rows of SoftDeleteTable.
+// Returned Querier is tied to the same DB or TX.
+func (q *Querier) WithDeleted() *Querier {
+	newQ := q.clone()
+	newQ.withDeleted = true
+	return newQ
+}
+
+// OnMaster returns a copy of Querier which sends all queries to master connection,
+// bypassing RoutingPolicy. Returned Querier is tied to the same DB or TX.
+func (q *Querier) OnMaster() *Querier {
+	newQ := q.clone()
+	newQ.route = RouteMaster
+	return newQ
+}
+
+// OnReplica returns a copy of Querier which sends all queries to a random slave connection,
+// bypassing RoutingPolicy. Queries are still sent to master if DB has no slaves,
+// and to transaction's connection if Querier is tied to TX.
+// Returned Querier is tied to the same DB or TX.
+func (q *Querier) OnReplica() *Querier {
+	newQ := q.clone()
+	newQ.route = RouteReplica
+	return newQ
+}
+
+// expandTail renders TailExpression passed as a single argument with placeholders starting from given index,
+// and appends it to tail. Otherwise, it returns tail and args as is.
+func (q *Querier) expandTail(tail string, args []interface{}, start int) (string, []interface{}) {
+	if len(args) != 1 {
+		return tail, args
+	}
+	e, ok := args[0].(TailExpression)
+	if !ok {
+		return tail, args
+	}
+
+	t, args := e.Tail(q.Dialect, start)
+	if tail != "" {
+		t = tail + " " + t
+	}
+	return t, args
+}
+
 // QualifiedView returns quoted qualified view name.
 func (q *Querier) QualifiedView(view View) string {
 	v := q.QuoteIdentifier(view.Name())
@@ -117,14 +193,21 @@ func (q *Querier) QualifiedColumns(view View) []string {
 
 // Exec executes a query without returning any rows.
 // The args are for any placeholder parameters in the query.
+// Errors are wrapped into QueryError.
 func (q *Querier) Exec(query string, args ...interface{}) (sql.Result, error) {
-	q.logBefore(query, args)
-	start := time.Now()
+	dbtxCtx, r := q.selectDBTXContext(query)
+	res := q.intercept(q.newCall(OpExec, query, args, r), func(ctx context.Context, call Call) CallResult {
+		q.logBefore(call.Query, call.Args)
+		start := time.Now()
 
-	dbtxCtx := q.selectDBTXContext(query)
-	res, err := dbtxCtx.ExecContext(q.ctx, query, args...)
-	q.logAfter(query, args, time.Since(start), err)
-	return res, err
+		res, err := dbtxCtx.ExecContext(ctx, call.Query, call.Args...)
+		if r != nil && q.replicas.record(r, time.Since(start), err, isNotSentError(err)) {
+			res, err = q.dbtxCtx.ExecContext(ctx, call.Query, call.Args...)
+		}
+		q.logAfter(call.Query, call.Args, time.Since(start), err)
+		return CallResult{Result: res, Err: q.wrapError(call.Query, err)}
+	})
+	return res.Result, res.Err
 }
 
 // ExecContext just calls q.WithContext(ctx).Exec(query, args...), and that form should be used instead.
@@ -135,14 +218,21 @@ func (q *Querier) ExecContext(ctx context.Context, query string, args ...interfa
 
 // Query executes a query that returns rows, typically a SELECT.
 // The args are for any placeholder parameters in the query.
+// Errors are wrapped into QueryError.
 func (q *Querier) Query(query string, args ...interface{}) (*sql.Rows, error) {
-	q.logBefore(query, args)
-	start := time.Now()
+	dbtxCtx, r := q.selectDBTXContext(query)
+	res := q.intercept(q.newCall(OpQuery, query, args, r), func(ctx context.Context, call Call) CallResult {
+		q.logBefore(call.Query, call.Args)
+		start := time.Now()
 
-	dbtxCtx := q.selectDBTXContext(query)
-	rows, err := dbtxCtx.QueryContext(q.ctx, query, args...)
-	q.logAfter(query, args, time.Since(start), err)
-	return rows, err
+		rows, err := dbtxCtx.QueryContext(ctx, call.Query, call.Args...)
+		if r != nil && q.replicas.record(r, time.Since(start), err, true) {
+			rows, err = q.dbtxCtx.QueryContext(ctx, call.Query, call.Args...)
+		}
+		q.logAfter(call.Query, call.Args, time.Since(start), err)
+		return CallResult{Rows: rows, Err: q.wrapError(call.Query, err)}
+	})
+	return res.Rows, res.Err
 }
 
 // QueryContext just calls q.WithContext(ctx).Query(query, args...), and that form should be used instead.
@@ -153,14 +243,44 @@ func (q *Querier) QueryContext(ctx context.Context, query string, args ...interf
 
 // QueryRow executes a query that is expected to return at most one row.
 // QueryRow always returns a non-nil value. Errors are deferred until Row's Scan method is called.
+// It panics if interceptor returned a result without Row (see CallResult).
 func (q *Querier) QueryRow(query string, args ...interface{}) *sql.Row {
-	q.logBefore(query, args)
-	start := time.Now()
+	res := q.queryRow(query, args)
+	if res.Row == nil {
+		panic(fmt.Sprintf("reform: interceptor returned no Row for QueryRow (error: %v)", res.Err))
+	}
+	return res.Row
+}
+
+// queryRow executes a query that is expected to return at most one row via Querier's interceptors.
+func (q *Querier) queryRow(query string, args []interface{}) CallResult {
+	dbtxCtx, r := q.selectDBTXContext(query)
+	return q.intercept(q.newCall(OpQueryRow, query, args, r), func(ctx context.Context, call Call) CallResult {
+		q.logBefore(call.Query, call.Args)
+		start := time.Now()
+
+		// connection errors are available before Scan, so replica's failures are recorded and handled
+		row := dbtxCtx.QueryRowContext(ctx, call.Query, call.Args...)
+		if r != nil && q.replicas.record(r, time.Since(start), row.Err(), true) {
+			row = q.dbtxCtx.QueryRowContext(ctx, call.Query, call.Args...)
+		}
+		q.logAfter(call.Query, call.Args, time.Since(start), nil)
+		return CallResult{Row: row}
+	})
+}
 
-	dbtxCtx := q.selectDBTXContext(query)
-	row := dbtxCtx.QueryRowContext(q.ctx, query, args...)
-	q.logAfter(query, args, time.Since(start), nil)
-	return row
+// scanRow executes a query that is expected to return at most one row and scans it to dest.
+// Unlike QueryRow, it returns an error if interceptor returned a result without Row (as is, like Exec and Query).
+// Scan errors are wrapped into QueryError.
+func (q *Querier) scanRow(dest []interface{}, query string, args ...interface{}) error {
+	res := q.queryRow(query, args)
+	if res.Row == nil {
+		if res.Err == nil {
+			return errors.New("reform: interceptor returned neither Row nor error for QueryRow")
+		}
+		return res.Err
+	}
+	return q.wrapError(query, res.Row.Scan(dest...))
 }
 
 // QueryRowContext just calls q.WithContext(ctx).QueryRow(query, args...), and that form should be used instead.
@@ -169,10 +289,17 @@ func (q *Querier) QueryRowContext(ctx context.Context, query string, args ...int
 	return q.WithContext(ctx).QueryRow(query, args...)
 }
 
+// IsInTransaction returns true if Querier is tied to TX, either on master or on replica (see Replica).
 func (q *Querier) IsInTransaction() bool {
 	return q.inTransaction
 }
 
+// Replica returns the name of replica in DB's ReplicaPool which Querier's transaction runs on,
+// or empty string if Querier is not tied to TX, or if that transaction runs on master.
+func (q *Querier) Replica() string {
+	return q.replica
+}
+
 func (q *Querier) AddOnCommitCall(f func() error) {
 	if !q.inTransaction {
 		panic("OnCommit callback added outside transaction")
@@ -181,23 +308,73 @@ func (q *Querier) AddOnCommitCall(f func() error) {
 	q.onCommitCalls = append(q.onCommitCalls, f)
 }
 
-// QuerierForRead возвращает Querier в мастер БД или в первую реплику.
+// QuerierForRead возвращает Querier в мастер БД или в одну из здоровых реплик (см. ReplicaPool).
 func (q *Querier) QuerierForRead() *Querier {
-	if q.inTransaction || len(q.slaves) == 0 {
+	if q.inTransaction || q.replicas == nil {
+		return q
+	}
+	r := q.replicas.pick()
+	if r == nil {
 		return q
 	}
 
-	return newQuerier(q.ctx, q.slaves[0], q.tag, q.Dialect, q.Logger, false, nil, nil)
+	newQ := q.clone()
+	newQ.route = RouteReplica
+	newQ.pinned = r
+	return newQ
 }
 
-func (q *Querier) selectDBTXContext(query string) DBTXContext {
-	if q.inTransaction || len(q.slaves) == 0 || !strings.HasPrefix(strings.TrimSpace(query), "SELECT") {
-		return q.dbtxCtx
+// newCall returns Call for intercepting operation made by Querier on master, transaction's or given replica.
+func (q *Querier) newCall(op Operation, query string, args []interface{}, r *replica) Call {
+	call := Call{
+		Op:      op,
+		Query:   query,
+		Args:    args,
+		Tag:     q.tag,
+		Replica: q.replica,
+	}
+	if r != nil {
+		call.Replica = r.name
+	}
+	return call
+}
+
+// selectDBTXContext returns connection for given query: transaction's or master connection,
+// or replica's connection if query is routed to replica. In the latter case, replica is also returned.
+func (q *Querier) selectDBTXContext(query string) (DBTXContext, *replica) {
+	ryw := readYourWritesFrom(q.ctx)
+	if ryw == nil && (q.inTransaction || q.replicas == nil || q.route == RouteMaster) {
+		return q.dbtxCtx, nil
 	}
 
-	//nolint:gosec
-	ind := rand.Intn(len(q.slaves))
-	return q.slaves[ind]
+	routing := q.routing
+	if routing == nil {
+		routing = DefaultRoutingPolicy{}
+	}
+	route := routing.Route(query)
+	if ryw != nil {
+		if route != RouteReplica {
+			ryw.wrote()
+		} else if ryw.active() {
+			route = RouteMaster
+		}
+	}
+	if q.route != 0 {
+		route = q.route
+	}
+
+	if q.inTransaction || q.replicas == nil || route != RouteReplica {
+		return q.dbtxCtx, nil
+	}
+
+	r := q.pinned
+	if r == nil {
+		r = q.replicas.pick()
+	}
+	if r == nil {
+		return q.dbtxCtx, nil
+	}
+	return r.db, r
 }
 
 // check interfaces
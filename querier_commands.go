@@ -2,9 +2,216 @@ package reform
 
 import (
 	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
 	"strings"
+	"sync/atomic"
+	"time"
 )
 
+// isPKColumnIndex returns true if column index i is present in a slice of primary key column indexes.
+func isPKColumnIndex(pks []uint, i int) bool {
+	for _, pk := range pks {
+		if int(pk) == i {
+			return true
+		}
+	}
+	return false
+}
+
+// withoutPK returns new slices of columns and values without primary key columns of given table.
+func withoutPK(table Table, columnsIn []string, valuesIn []interface{}) (columns []string, values []interface{}) {
+	pks := table.PKColumnIndexes()
+	columns = make([]string, 0, len(columnsIn)-len(pks))
+	values = make([]interface{}, 0, len(valuesIn)-len(pks))
+	for i, c := range columnsIn {
+		if isPKColumnIndex(pks, i) {
+			continue
+		}
+		columns = append(columns, c)
+		values = append(values, valuesIn[i])
+	}
+	return
+}
+
+// withoutAutoCreate returns columns and values without AutoCreateTable's autocreate column.
+func withoutAutoCreate(table Table, columnsIn []string, valuesIn []interface{}) (columns []string, values []interface{}) {
+	t, ok := table.(AutoCreateTable)
+	if !ok {
+		return columnsIn, valuesIn
+	}
+
+	autoCreate := table.Columns()[t.AutoCreateColumnIndex()]
+	columns = make([]string, 0, len(columnsIn))
+	values = make([]interface{}, 0, len(valuesIn))
+	for i, c := range columnsIn {
+		if c == autoCreate {
+			continue
+		}
+		columns = append(columns, c)
+		values = append(values, valuesIn[i])
+	}
+	return
+}
+
+// pkColumnsAndValues returns primary key columns and values of given record.
+func pkColumnsAndValues(record Record) (columns []string, values []interface{}) {
+	table := record.Table()
+	pks := table.PKColumnIndexes()
+	allColumns := table.Columns()
+	allValues := record.Values()
+	columns = make([]string, len(pks))
+	values = make([]interface{}, len(pks))
+	for i, pk := range pks {
+		columns[i] = allColumns[pk]
+		values[i] = allValues[pk]
+	}
+	return
+}
+
+// hasAutoPK returns true if given struct is a record with single-column primary key
+// which may be generated by SQL database.
+func hasAutoPK(str Struct) bool {
+	record, _ := str.(Record)
+	if record == nil {
+		return false
+	}
+	return len(record.Table().PKColumnIndexes()) == 1
+}
+
+// hasIntegerPK returns true if given record has single-column primary key of integer type.
+func hasIntegerPK(record Record) bool {
+	switch reflect.ValueOf(record.PKPointer()).Elem().Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	default:
+		return false
+	}
+}
+
+// pkTail returns WHERE tail for given primary key columns with placeholders starting from given index.
+// If view is not empty, columns are qualified with it.
+func (q *Querier) pkTail(view string, columns []string, start int) string {
+	conds := make([]string, len(columns))
+	for i, c := range columns {
+		qi := q.QuoteIdentifier(c)
+		if view != "" {
+			qi = q.QuoteIdentifier(view) + "." + qi
+		}
+		conds[i] = qi + " = " + q.Placeholder(start+i)
+	}
+	return "WHERE " + strings.Join(conds, " AND ")
+}
+
+// versionLock represents optimistic locking state for record of VersionedTable.
+type versionLock struct {
+	column  string
+	pointer interface{}
+	current interface{}
+	next    interface{}
+}
+
+// newVersionLock returns optimistic locking state for given record, or nil if its table is not VersionedTable.
+func newVersionLock(record Record) *versionLock {
+	table, ok := record.Table().(VersionedTable)
+	if !ok {
+		return nil
+	}
+
+	i := table.VersionColumnIndex()
+	current := record.Values()[i]
+	next := reflect.New(reflect.TypeOf(current)).Elem()
+	switch v := reflect.ValueOf(current); v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		next.SetInt(v.Int() + 1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		next.SetUint(v.Uint() + 1)
+	default:
+		panic(fmt.Sprintf("reform: unexpected version column type %T. Please report this bug.", current))
+	}
+
+	return &versionLock{
+		column:  table.Columns()[i],
+		pointer: record.Pointers()[i],
+		current: current,
+		next:    next.Interface(),
+	}
+}
+
+// set returns columns and values for UPDATE statement with incremented version.
+func (l *versionLock) set(columns []string, values []interface{}) ([]string, []interface{}) {
+	return setColumn(columns, values, l.column, l.next)
+}
+
+// where returns primary key columns and values with added current version.
+func (l *versionLock) where(pkColumns []string, pkValues []interface{}) ([]string, []interface{}) {
+	return append(pkColumns, l.column), append(pkValues, l.current)
+}
+
+// apply sets incremented version to record's field.
+func (l *versionLock) apply() {
+	setField(l.pointer, l.next)
+}
+
+// setColumn replaces value of given column in columns and values, or appends them if column is absent.
+func setColumn(columns []string, values []interface{}, column string, value interface{}) ([]string, []interface{}) {
+	for i, c := range columns {
+		if c == column {
+			values[i] = value
+			return columns, values
+		}
+	}
+	return append(columns, column), append(values, value)
+}
+
+// isZeroTime returns true if given value of time.Time or *time.Time field is nil or zero time.
+func isZeroTime(value interface{}) bool {
+	switch v := value.(type) {
+	case time.Time:
+		return v.IsZero()
+	case *time.Time:
+		return v == nil || v.IsZero()
+	default:
+		panic(fmt.Sprintf("reform: unexpected timestamp column type %T. Please report this bug.", value))
+	}
+}
+
+// setTime sets time.Time or *time.Time field by pointer to given time.
+func setTime(pointer interface{}, t time.Time) {
+	switch p := pointer.(type) {
+	case *time.Time:
+		*p = t
+	case **time.Time:
+		*p = &t
+	default:
+		panic(fmt.Sprintf("reform: unexpected timestamp column type %T. Please report this bug.", pointer))
+	}
+}
+
+// setAutoCreate sets struct's AutoCreateTable field to the current time if it is not set yet,
+// and returns its column, or empty string if there is no such field.
+func (q *Querier) setAutoCreate(str Struct) string {
+	table, ok := str.View().(AutoCreateTable)
+	if !ok {
+		return ""
+	}
+
+	i := table.AutoCreateColumnIndex()
+	if isZeroTime(str.Values()[i]) {
+		setTime(str.Pointers()[i], q.now())
+	}
+	return table.Columns()[i]
+}
+
+// setAutoUpdate sets struct's AutoUpdateTable field to the current time.
+func (q *Querier) setAutoUpdate(str Struct) {
+	if table, ok := str.View().(AutoUpdateTable); ok {
+		setTime(str.Pointers()[table.AutoUpdateColumnIndex()], q.now())
+	}
+}
+
 func filteredColumnsAndValues(str Struct, columnsIn []string, isUpdate bool) (columns []string, values []interface{}, err error) {
 	columnsSet := make(map[string]struct{}, len(columnsIn))
 	for _, c := range columnsIn {
@@ -19,15 +226,15 @@ func filteredColumnsAndValues(str Struct, columnsIn []string, isUpdate bool) (co
 	values = make([]interface{}, 0, len(columns))
 
 	record, _ := str.(Record)
-	var pk uint
+	var pks []uint
 	if record != nil {
-		pk = view.(Table).PKColumnIndex()
+		pks = view.(Table).PKColumnIndexes()
 	}
 
 	for i, c := range allColumns {
 		if _, ok := columnsSet[c]; ok {
-			if isUpdate && record != nil && i == int(pk) {
-				err = fmt.Errorf("reform: will not update PK column: %s", c)
+			if isUpdate && record != nil && isPKColumnIndex(pks, i) {
+				err = &PKColumnUpdateError{Column: c}
 				return
 			}
 			delete(columnsSet, c)
@@ -42,8 +249,8 @@ func filteredColumnsAndValues(str Struct, columnsIn []string, isUpdate bool) (co
 		for c := range columnsSet {
 			columns = append(columns, c)
 		}
-		// TODO make exported type for that error
-		err = fmt.Errorf("reform: unexpected columns: %v", columns)
+		sort.Strings(columns)
+		err = &UnexpectedColumnsError{Columns: columns}
 		return
 	}
 
@@ -61,8 +268,10 @@ func (q *Querier) insert(str Struct, columns []string, values []interface{}) err
 	lastInsertIdMethod := q.LastInsertIdMethod()
 	defaultValuesMethod := q.DefaultValuesMethod()
 
+	// composite primary key is never generated by SQL database
+	autoPK := hasAutoPK(str)
 	var pk uint
-	if record != nil {
+	if autoPK {
 		pk = view.(Table).PKColumnIndex()
 	}
 
@@ -71,7 +280,7 @@ func (q *Querier) insert(str Struct, columns []string, values []interface{}) err
 	if len(columns) > 0 || defaultValuesMethod == EmptyLists {
 		query += " (" + strings.Join(columns, ", ") + ")"
 	}
-	if record != nil && lastInsertIdMethod == OutputInserted {
+	if autoPK && lastInsertIdMethod == OutputInserted {
 		query += fmt.Sprintf(" OUTPUT INSERTED.%s", q.QuoteIdentifier(view.Columns()[pk]))
 	}
 	if len(placeholders) > 0 || defaultValuesMethod == EmptyLists {
@@ -79,7 +288,7 @@ func (q *Querier) insert(str Struct, columns []string, values []interface{}) err
 	} else {
 		query += " DEFAULT VALUES"
 	}
-	if record != nil && lastInsertIdMethod == Returning {
+	if autoPK && lastInsertIdMethod == Returning {
 		query += fmt.Sprintf(" RETURNING %s", q.QuoteIdentifier(view.Columns()[pk]))
 	}
 
@@ -89,7 +298,7 @@ func (q *Querier) insert(str Struct, columns []string, values []interface{}) err
 		if err != nil {
 			return err
 		}
-		if record != nil && !record.HasPK() {
+		if autoPK && !record.HasPK() {
 			id, err := res.LastInsertId()
 			if err != nil {
 				return err
@@ -104,8 +313,8 @@ func (q *Querier) insert(str Struct, columns []string, values []interface{}) err
 
 	case Returning, OutputInserted:
 		var err error
-		if record != nil {
-			err = q.QueryRow(query, values...).Scan(record.PKPointer())
+		if autoPK {
+			err = q.scanRow([]interface{}{record.PKPointer()}, query, values...)
 		} else {
 			_, err = q.Exec(query, values...)
 		}
@@ -117,6 +326,11 @@ func (q *Querier) insert(str Struct, columns []string, values []interface{}) err
 }
 
 func (q *Querier) beforeInsert(str Struct) error {
+	q.setAutoCreate(str)
+
+	if bi, ok := str.(BeforeInserterContext); ok {
+		return bi.BeforeInsertContext(q.ctx, q)
+	}
 	if bi, ok := str.(BeforeInserter); ok {
 		if err := bi.BeforeInsert(); err != nil {
 			return err
@@ -126,10 +340,23 @@ func (q *Querier) beforeInsert(str Struct) error {
 	return nil
 }
 
+func (q *Querier) afterInsert(str Struct) error {
+	if ai, ok := str.(AfterInserterContext); ok {
+		return ai.AfterInsertContext(q.ctx, q)
+	}
+	if ai, ok := str.(AfterInserter); ok {
+		return ai.AfterInsert()
+	}
+	return nil
+}
+
 // Insert inserts a struct into SQL database table.
 // If str implements BeforeInserter, it calls BeforeInsert() before doing so.
+// If str implements AfterInserter, it calls AfterInsert() after that and after filling primary key.
+// For AutoCreateTable, it sets autocreate field to the current time if it is not set yet, before BeforeInsert().
 //
-// It fills record's primary key field.
+// It fills record's single-column primary key field.
+// Composite primary key fields are always inserted as they are.
 func (q *Querier) Insert(str Struct) error {
 	if err := q.beforeInsert(str); err != nil {
 		return err
@@ -138,70 +365,69 @@ func (q *Querier) Insert(str Struct) error {
 	view := str.View()
 	values := str.Values()
 	columns := view.Columns()
-	record, _ := str.(Record)
 
-	if record != nil {
+	// cut primary key
+	if hasAutoPK(str) && !str.(Record).HasPK() {
 		pk := view.(Table).PKColumnIndex()
-
-		// cut primary key
-		if !record.HasPK() {
-			values = append(values[:pk], values[pk+1:]...)
-			columns = append(columns[:pk], columns[pk+1:]...)
-		}
+		values = append(values[:pk], values[pk+1:]...)
+		columns = append(columns[:pk], columns[pk+1:]...)
 	}
 
-	return q.insert(str, columns, values)
+	if err := q.insert(str, columns, values); err != nil {
+		return err
+	}
+	takeSnapshot(str)
+	return q.afterInsert(str)
 }
 
 // InsertColumns inserts a struct into SQL database table with specified columns.
 // Other columns are omitted from generated INSERT statement.
 // If str implements BeforeInserter, it calls BeforeInsert() before doing so.
+// If str implements AfterInserter, it calls AfterInsert() after that and after filling primary key.
+// For AutoCreateTable, it sets and inserts autocreate column like Insert, even if it is not specified.
 //
-// It fills record's primary key field.
+// It fills record's single-column primary key field.
 func (q *Querier) InsertColumns(str Struct, columns ...string) error {
 	if err := q.beforeInsert(str); err != nil {
 		return err
 	}
 
+	if column := q.setAutoCreate(str); column != "" {
+		columns = append(columns, column)
+	}
+
 	columns, values, err := filteredColumnsAndValues(str, columns, false)
 	if err != nil {
 		return err
 	}
 
-	return q.insert(str, columns, values)
-}
-
-// InsertMulti inserts several structs into SQL database table with single query.
-// If they implement BeforeInserter, it calls BeforeInsert() before doing so.
-//
-// All structs should belong to the same view/table.
-// All records should either have or not have primary key set.
-// It doesn't fill primary key fields.
-// Given all these limitations, most users should use Querier.Insert in a loop, not this method.
-func (q *Querier) InsertMulti(structs ...Struct) error {
-	if len(structs) == 0 {
-		return nil
+	if err = q.insert(str, columns, values); err != nil {
+		return err
 	}
+	takeSnapshot(str)
+	return q.afterInsert(str)
+}
 
+// multiColumnsAndValues checks structs for InsertMulti-like methods, sets their autocreate fields,
+// and calls BeforeInsert() for them.
+// It returns columns (without primary key if it is absent) and values of all structs.
+func (q *Querier) multiColumnsAndValues(method string, structs []Struct) (columns []string, values []interface{}, err error) {
 	// check that view is the same
 	view := structs[0].View()
 	for _, str := range structs {
 		if str.View() != view {
-			return fmt.Errorf("reform: different tables in InsertMulti: %s and %s", view.Name(), str.View().Name())
+			err = &DifferentTablesError{Method: method, First: view.Name(), Second: str.View().Name()}
+			return
 		}
 	}
 
-	var err error
 	for _, str := range structs {
-		if bi, ok := str.(BeforeInserter); ok {
-			e := bi.BeforeInsert()
-			if err == nil {
-				err = e
-			}
+		if e := q.beforeInsert(str); err == nil {
+			err = e
 		}
 	}
 	if err != nil {
-		return err
+		return
 	}
 
 	// check if all PK are present or all are absent
@@ -210,44 +436,624 @@ func (q *Querier) InsertMulti(structs ...Struct) error {
 		for _, str := range structs {
 			rec, _ := str.(Record)
 			if record.HasPK() != rec.HasPK() {
-				return fmt.Errorf("reform: PK in present in one struct and absent in other: first: %s, second: %s",
-					record, rec)
+				err = &PKMismatchError{First: record, Second: rec}
+				return
 			}
 		}
 	}
 
-	columns := view.Columns()
+	columns = view.Columns()
+	cutPK := hasAutoPK(record) && !record.HasPK()
+	var pk uint
+	if cutPK {
+		pk = view.(Table).PKColumnIndex()
+		columns = append(columns[:pk], columns[pk+1:]...)
+	}
+
+	values = make([]interface{}, 0, len(columns)*len(structs))
+	for _, str := range structs {
+		v := str.Values()
+		if cutPK {
+			v = append(v[:pk], v[pk+1:]...)
+		}
+		values = append(values, v...)
+	}
+	return
+}
+
+// valuesRows returns given number of VALUES rows with placeholders for given number of columns.
+func (q *Querier) valuesRows(columns, count int) []string {
+	placeholders := q.Placeholders(1, columns*count)
+	rows := make([]string, count)
+	for i := range rows {
+		rows[i] = "(" + strings.Join(placeholders[columns*i:columns*(i+1)], ", ") + ")"
+	}
+	return rows
+}
+
+// multiChunks calls f for consecutive chunks of structs and their values, so that number of placeholders
+// in a single query for each chunk does not exceed dialect's limit. It stops on the first error.
+func (q *Querier) multiChunks(structs []Struct, columns []string, values []interface{},
+	f func(structs []Struct, values []interface{}) error) error {
+	size := len(structs)
+	if limit := q.MaxPlaceholders(); limit > 0 && len(columns) > 0 && len(columns)*size > limit {
+		size = limit / len(columns)
+		if size == 0 {
+			return fmt.Errorf("reform: %d columns exceed the limit of %d placeholders", len(columns), limit)
+		}
+	}
+
+	for start := 0; start < len(structs); start += size {
+		end := start + size
+		if end > len(structs) {
+			end = len(structs)
+		}
+		if err := f(structs[start:end], values[start*len(columns):end*len(columns)]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InsertMulti inserts several structs into SQL database table with single query.
+// If they implement BeforeInserter, it calls BeforeInsert() before doing so.
+// If they implement AfterInserter, it calls AfterInsert() after all queries and after filling primary keys
+// (or attempting to, see below).
+//
+// If the number of placeholders exceeds dialect's limit, several queries are used.
+// They are executed in the transaction if Querier is a part of it, and independently otherwise.
+//
+// All structs should belong to the same view/table.
+// All records should either have or not have primary key set.
+// If records have single-column primary key unset, it fills them with generated values in order of records:
+//   - with RETURNING clause for dialects with Returning method (PostgreSQL), if primary key has integer type
+//     and is generated by sequence (serial or identity column); otherwise primary keys are not filled;
+//   - with MERGE statement and OUTPUT clause for dialects with OutputInserted method (MS SQL);
+//   - with sql.Result.LastInsertId() for dialects with LastInsertId method, if dialect's LastInsertIdMultiMethod
+//     guarantees consecutive primary keys. For MySQL, reform checks that innodb_autoinc_lock_mode is 0 ("traditional")
+//     or 1 ("consecutive"), and auto_increment_increment is 1, with an additional query; otherwise primary keys
+//     are not filled. For SQLite3, primary keys are always consecutive, unless triggers insert rows into the same table.
+func (q *Querier) InsertMulti(structs ...Struct) error {
+	if len(structs) == 0 {
+		return nil
+	}
+
+	columns, values, err := q.multiColumnsAndValues("InsertMulti", structs)
+	if err != nil {
+		return err
+	}
+
 	for i, c := range columns {
 		columns[i] = q.QuoteIdentifier(c)
 	}
+	err = q.multiChunks(structs, columns, values, func(structs []Struct, values []interface{}) error {
+		return q.insertMulti(structs, columns, values)
+	})
+	if err != nil {
+		return err
+	}
 
-	var pk uint
-	if record != nil && !record.HasPK() {
-		pk = view.(Table).PKColumnIndex()
-		columns = append(columns[:pk], columns[pk+1:]...)
+	for _, str := range structs {
+		takeSnapshot(str)
+		if e := q.afterInsert(str); err == nil {
+			err = e
+		}
 	}
+	return err
+}
 
-	placeholders := q.Placeholders(1, len(columns)*len(structs))
-	query := fmt.Sprintf("%s INTO %s (%s) VALUES ",
+// insertMulti inserts several structs with given quoted columns and values with single query for InsertMulti.
+func (q *Querier) insertMulti(structs []Struct, columns []string, values []interface{}) error {
+	view := structs[0].View()
+	rows := q.valuesRows(len(columns), len(structs))
+	query := fmt.Sprintf("%s INTO %s (%s) VALUES %s",
 		q.startQuery("INSERT"),
 		q.QualifiedView(view),
 		strings.Join(columns, ", "),
+		strings.Join(rows, ", "),
 	)
-	for i := 0; i < len(structs); i++ {
-		query += fmt.Sprintf("(%s), ", strings.Join(placeholders[len(columns)*i:len(columns)*(i+1)], ", "))
+
+	record, _ := structs[0].(Record)
+	if !hasAutoPK(record) || record.HasPK() {
+		_, err := q.Exec(query, values...)
+		return err
 	}
-	query = query[:len(query)-2] // cut last ", "
 
-	values := make([]interface{}, 0, len(placeholders))
-	for _, str := range structs {
-		v := str.Values()
-		if record != nil && !record.HasPK() {
-			v = append(v[:pk], v[pk+1:]...)
+	pkColumn := q.QuoteIdentifier(view.Columns()[record.Table().PKColumnIndex()])
+	switch q.LastInsertIdMethod() {
+	case LastInsertId:
+		return q.insertMultiLastInsertId(structs, query, values)
+
+	case Returning:
+		// PostgreSQL doesn't guarantee order of rows for RETURNING clause, but generates values (with sequences)
+		// in order of VALUES, so record index is a rank of integer primary key
+		if !hasIntegerPK(record) {
+			_, err := q.Exec(query, values...)
+			return err
 		}
-		values = append(values, v...)
+		inserted := q.QuoteIdentifier("reform_inserted")
+		query = fmt.Sprintf("WITH %s AS (%s RETURNING %s) SELECT row_number() OVER (ORDER BY %s) - 1, %s FROM %s",
+			inserted, query, pkColumn, pkColumn, pkColumn, inserted,
+		)
+		return q.scanMultiPKs(structs, query, values, true)
+
+	case OutputInserted:
+		// MS SQL doesn't guarantee order of rows for OUTPUT clause of INSERT,
+		// so we use MERGE which allows to output source row index
+		index := q.QuoteIdentifier("reform_index")
+		sourceColumns := make([]string, len(columns))
+		for i, c := range columns {
+			sourceColumns[i] = "source." + c
+		}
+		for i, row := range rows {
+			rows[i] = "(" + strconv.Itoa(i) + ", " + row[1:]
+		}
+		query = fmt.Sprintf("%s INTO %s USING (VALUES %s) AS source (%s) ON 1 = 0 "+
+			"WHEN NOT MATCHED THEN INSERT (%s) VALUES (%s) OUTPUT source.%s, INSERTED.%s;",
+			q.startQuery("MERGE"),
+			q.QualifiedView(view),
+			strings.Join(rows, ", "),
+			strings.Join(append([]string{index}, columns...), ", "),
+			strings.Join(columns, ", "),
+			strings.Join(sourceColumns, ", "),
+			index, pkColumn,
+		)
+		return q.scanMultiPKs(structs, query, values, true)
+
+	default:
+		panic("reform: Unhandled LastInsertIdMethod. Please report this bug.")
+	}
+}
+
+// insertMultiLastInsertId executes multi-row INSERT query for InsertMulti
+// and fills primary keys from sql.Result.LastInsertId() if they are known to be consecutive.
+func (q *Querier) insertMultiLastInsertId(structs []Struct, query string, values []interface{}) error {
+	method := q.LastInsertIdMultiMethod()
+	if method == FirstInsertId {
+		consecutive, err := q.consecutiveAutoIncrement()
+		if err != nil {
+			return err
+		}
+		if !consecutive {
+			method = NoLastInsertIdMulti
+		}
+	}
+
+	res, err := q.Exec(query, values...)
+	if err != nil {
+		return err
+	}
+
+	var first int64
+	switch method {
+	case NoLastInsertIdMulti:
+		return nil
+	case FirstInsertId:
+		if first, err = res.LastInsertId(); err != nil {
+			return err
+		}
+	case LastRowInsertId:
+		if first, err = res.LastInsertId(); err != nil {
+			return err
+		}
+		first -= int64(len(structs) - 1)
+	default:
+		panic("reform: Unhandled LastInsertIdMultiMethod. Please report this bug.")
+	}
+
+	for i, str := range structs {
+		// TODO optimize to avoid using reflection
+		// https://github.com/go-reform/reform/issues/269
+		SetPK(str.(Record), first+int64(i))
+	}
+	return nil
+}
+
+// autoIncrementMode caches the result of consecutiveAutoIncrement for DB and its transactions.
+type autoIncrementMode struct {
+	state atomic.Int32 // 0 - not checked yet, 1 - consecutive, 2 - not consecutive
+}
+
+// consecutiveAutoIncrement returns true if auto-increment values for multi-row INSERT with known number of rows
+// are consecutive. That's true only in "traditional" and "consecutive" lock modes with increment 1; see
+// https://dev.mysql.com/doc/refman/8.0/en/innodb-auto-increment-handling.html
+//
+// Settings are queried on master once per DB; server is expected to not change them.
+func (q *Querier) consecutiveAutoIncrement() (bool, error) {
+	mode := q.autoIncrement
+	if mode != nil {
+		switch mode.state.Load() {
+		case 1:
+			return true, nil
+		case 2:
+			return false, nil
+		}
+	}
+
+	var lockMode, increment int64
+	query := "SELECT @@innodb_autoinc_lock_mode, @@auto_increment_increment"
+	if err := q.OnMaster().scanRow([]interface{}{&lockMode, &increment}, query); err != nil {
+		return false, err
+	}
+	consecutive := (lockMode == 0 || lockMode == 1) && increment == 1
+
+	if mode != nil {
+		state := int32(2)
+		if consecutive {
+			state = 1
+		}
+		mode.state.Store(state)
+	}
+	return consecutive, nil
+}
+
+// scanMultiPKs executes query for InsertMulti and scans returned primary keys into records.
+// If indexed is true, each row contains record index before primary key, otherwise rows are in records order.
+func (q *Querier) scanMultiPKs(structs []Struct, query string, values []interface{}, indexed bool) error {
+	rows, err := q.Query(query, values...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var n int
+	for ; rows.Next(); n++ {
+		i := n
+		if indexed {
+			var pk interface{}
+			if err = rows.Scan(&i, &pk); err != nil {
+				return q.wrapError(query, err)
+			}
+			if i < 0 || i >= len(structs) {
+				return fmt.Errorf("reform: unexpected record index %d", i)
+			}
+			SetPK(structs[i].(Record), pk)
+			continue
+		}
+
+		if i >= len(structs) {
+			return fmt.Errorf("reform: expected %d rows, got more", len(structs))
+		}
+		if err = rows.Scan(structs[i].(Record).PKPointer()); err != nil {
+			return q.wrapError(query, err)
+		}
+	}
+	// errors of RETURNING queries (like unique violations) may be returned only there
+	if err = rows.Err(); err != nil {
+		return q.wrapError(query, err)
+	}
+	if n != len(structs) {
+		return fmt.Errorf("reform: expected %d rows, got %d", len(structs), n)
+	}
+	return nil
+}
+
+// upsertColumns checks conflict and update columns for Upsert-like methods against inserted columns,
+// and returns columns to update.
+func upsertColumns(view View, inserted, conflictColumns, updateColumns []string) ([]string, error) {
+	if len(conflictColumns) == 0 {
+		return nil, ErrNoConflictColumns
+	}
+
+	insertedSet := make(map[string]struct{}, len(inserted))
+	for _, c := range inserted {
+		insertedSet[c] = struct{}{}
+	}
+	conflictSet := make(map[string]struct{}, len(conflictColumns))
+	var unexpected []string
+	for _, c := range conflictColumns {
+		if _, ok := insertedSet[c]; !ok {
+			unexpected = append(unexpected, c)
+		}
+		conflictSet[c] = struct{}{}
+	}
+	for _, c := range updateColumns {
+		if _, ok := insertedSet[c]; !ok {
+			unexpected = append(unexpected, c)
+		}
+	}
+	if len(unexpected) > 0 {
+		return nil, &UnexpectedColumnsError{Columns: unexpected}
+	}
+
+	var pks []uint
+	if table, ok := view.(Table); ok {
+		pks = table.PKColumnIndexes()
+	}
+	isPK := func(column string) bool {
+		for i, c := range view.Columns() {
+			if c == column {
+				return isPKColumnIndex(pks, i)
+			}
+		}
+		return false
+	}
+
+	var autoCreate string
+	if table, ok := view.(AutoCreateTable); ok {
+		autoCreate = view.Columns()[table.AutoCreateColumnIndex()]
+	}
+	autoUpdate := autoUpdateColumn(view)
+
+	if len(updateColumns) == 0 {
+		for _, c := range inserted {
+			if _, ok := conflictSet[c]; ok || isPK(c) || c == autoCreate || c == autoUpdate {
+				continue
+			}
+			updateColumns = append(updateColumns, c)
+		}
+	} else {
+		columns := make([]string, 0, len(updateColumns))
+		for _, c := range updateColumns {
+			if isPK(c) {
+				return nil, &PKColumnUpdateError{Column: c}
+			}
+			if c != autoUpdate {
+				columns = append(columns, c)
+			}
+		}
+		updateColumns = columns
+	}
+
+	// make a no-op update so the row is returned (and counted) even if there is nothing to update
+	if len(updateColumns) == 0 {
+		updateColumns = conflictColumns[:1]
+	}
+
+	return updateColumns, nil
+}
+
+// autoUpdateColumn returns AutoUpdateTable's autoupdate column of given view, or empty string.
+func autoUpdateColumn(view View) string {
+	if table, ok := view.(AutoUpdateTable); ok {
+		return view.Columns()[table.AutoUpdateColumnIndex()]
+	}
+	return ""
+}
+
+// upsertArgs returns arguments for upsert query with given VALUES rows values.
+// For AutoUpdateTable, the current time for autoupdate column of updated rows is added.
+func (q *Querier) upsertArgs(view View, values []interface{}) []interface{} {
+	if autoUpdateColumn(view) == "" {
+		return values
+	}
+	return append(values[:len(values):len(values)], q.now())
+}
+
+// upsertQuery returns full upsert query for given view, inserted columns and VALUES rows.
+// If pkColumn is not empty, query returns or sets the last insert id to the value of that column.
+// For AutoUpdateTable, updated rows get autoupdate column set to the last placeholder's value (see upsertArgs).
+func (q *Querier) upsertQuery(view View, columns, rows, conflictColumns, updateColumns []string, pkColumn string) string {
+	quote := func(prefix string, columns []string) []string {
+		res := make([]string, len(columns))
+		for i, c := range columns {
+			res[i] = prefix + q.QuoteIdentifier(c)
+		}
+		return res
+	}
+
+	var autoUpdate string
+	if c := autoUpdateColumn(view); c != "" {
+		autoUpdate = q.QuoteIdentifier(c) + " = " + q.Placeholder(len(columns)*len(rows)+1)
+	}
+
+	switch q.UpsertMethod() {
+	case OnConflict:
+		set := make([]string, len(updateColumns))
+		for i, c := range updateColumns {
+			set[i] = fmt.Sprintf("%s = EXCLUDED.%s", q.QuoteIdentifier(c), q.QuoteIdentifier(c))
+		}
+		if autoUpdate != "" {
+			set = append(set, autoUpdate)
+		}
+		query := fmt.Sprintf("%s INTO %s (%s) VALUES %s ON CONFLICT (%s) DO UPDATE SET %s",
+			q.startQuery("INSERT"),
+			q.QualifiedView(view),
+			strings.Join(quote("", columns), ", "),
+			strings.Join(rows, ", "),
+			strings.Join(quote("", conflictColumns), ", "),
+			strings.Join(set, ", "),
+		)
+		if pkColumn != "" && q.LastInsertIdMethod() == Returning {
+			query += " RETURNING " + q.QuoteIdentifier(pkColumn)
+		}
+		return query
+
+	case OnDuplicateKeyUpdate:
+		set := make([]string, len(updateColumns))
+		for i, c := range updateColumns {
+			set[i] = fmt.Sprintf("%s = VALUES(%s)", q.QuoteIdentifier(c), q.QuoteIdentifier(c))
+		}
+		if autoUpdate != "" {
+			set = append(set, autoUpdate)
+		}
+		if pkColumn != "" && q.LastInsertIdMethod() == LastInsertId {
+			// make LastInsertId() return primary key of updated row
+			set = append(set, fmt.Sprintf("%s = LAST_INSERT_ID(%s)", q.QuoteIdentifier(pkColumn), q.QuoteIdentifier(pkColumn)))
+		}
+		return fmt.Sprintf("%s INTO %s (%s) VALUES %s ON DUPLICATE KEY UPDATE %s",
+			q.startQuery("INSERT"),
+			q.QualifiedView(view),
+			strings.Join(quote("", columns), ", "),
+			strings.Join(rows, ", "),
+			strings.Join(set, ", "),
+		)
+
+	case Merge:
+		on := make([]string, len(conflictColumns))
+		for i, c := range conflictColumns {
+			on[i] = fmt.Sprintf("target.%s = source.%s", q.QuoteIdentifier(c), q.QuoteIdentifier(c))
+		}
+		set := make([]string, len(updateColumns))
+		for i, c := range updateColumns {
+			set[i] = fmt.Sprintf("target.%s = source.%s", q.QuoteIdentifier(c), q.QuoteIdentifier(c))
+		}
+		if autoUpdate != "" {
+			set = append(set, "target."+autoUpdate)
+		}
+		query := fmt.Sprintf("%s INTO %s WITH (HOLDLOCK) AS target USING (VALUES %s) AS source (%s) ON %s "+
+			"WHEN MATCHED THEN UPDATE SET %s WHEN NOT MATCHED THEN INSERT (%s) VALUES (%s)",
+			q.startQuery("MERGE"),
+			q.QualifiedView(view),
+			strings.Join(rows, ", "),
+			strings.Join(quote("", columns), ", "),
+			strings.Join(on, " AND "),
+			strings.Join(set, ", "),
+			strings.Join(quote("", columns), ", "),
+			strings.Join(quote("source.", columns), ", "),
+		)
+		if pkColumn != "" && q.LastInsertIdMethod() == OutputInserted {
+			query += " OUTPUT INSERTED." + q.QuoteIdentifier(pkColumn)
+		}
+		return query + ";" // MERGE statement should be terminated by semicolon
+
+	default:
+		panic("reform: Unhandled UpsertMethod. Please report this bug.")
+	}
+}
+
+// Upsert inserts a struct into SQL database table, or updates existing row in case of conflict
+// on conflictColumns (typically, primary key or unique constraint columns).
+// If updateColumns are not given, all inserted columns except conflict, primary key,
+// and AutoCreateTable's autocreate columns are updated.
+// If str implements BeforeInserter, it calls BeforeInsert() before doing so.
+// If str implements AfterInserter and AfterSaver, it calls AfterInsert() and AfterSave() after that
+// and after filling primary key, for both inserted and updated row.
+// Autocreate field is set like for Insert. AutoUpdateTable's autoupdate column of updated row
+// is always set to the current time, but autoupdate field is not changed.
+//
+// Conflict columns should be present in generated INSERT statement.
+// MySQL uses any unique index to detect conflict and ignores conflictColumns for that purpose.
+//
+// It fills record's single-column primary key field for both inserted and updated row.
+func (q *Querier) Upsert(str Struct, conflictColumns []string, updateColumns ...string) error {
+	if err := q.upsert(str, conflictColumns, updateColumns); err != nil {
+		return err
+	}
+	takeSnapshot(str)
+	return q.afterUpsert(str)
+}
+
+// afterUpsert calls AfterInsert() and AfterSave() (or their context variants) for upserted struct.
+func (q *Querier) afterUpsert(str Struct) error {
+	if err := q.afterInsert(str); err != nil {
+		return err
+	}
+	if record, ok := str.(Record); ok {
+		return q.afterSave(record)
+	}
+	return nil
+}
+
+// upsert executes INSERT with conflict handling for Upsert and fills primary key.
+func (q *Querier) upsert(str Struct, conflictColumns, updateColumns []string) error {
+	view := str.View()
+	columns, values, err := q.multiColumnsAndValues("Upsert", []Struct{str})
+	if err != nil {
+		return err
+	}
+	if updateColumns, err = upsertColumns(view, columns, conflictColumns, updateColumns); err != nil {
+		return err
+	}
+
+	record, _ := str.(Record)
+	var pkColumn string
+	if hasAutoPK(str) {
+		pkColumn = view.Columns()[view.(Table).PKColumnIndex()]
 	}
 
-	_, err = q.Exec(query, values...)
+	rows := q.valuesRows(len(columns), 1)
+	query := q.upsertQuery(view, columns, rows, conflictColumns, updateColumns, pkColumn)
+	args := q.upsertArgs(view, values)
+
+	switch q.LastInsertIdMethod() {
+	case LastInsertId:
+		res, err := q.Exec(query, args...)
+		if err != nil {
+			return err
+		}
+		if pkColumn == "" || record.HasPK() {
+			return nil
+		}
+
+		if q.UpsertMethod() == OnDuplicateKeyUpdate {
+			id, err := res.LastInsertId()
+			if err != nil {
+				return err
+			}
+			SetPK(record, id)
+			return nil
+		}
+
+		// last insert id is not changed by UPDATE, so select primary key by conflict columns
+		args := make([]interface{}, len(conflictColumns))
+		for i, cc := range conflictColumns {
+			for j, c := range columns {
+				if c == cc {
+					args[i] = values[j]
+				}
+			}
+		}
+		query = fmt.Sprintf("%s %s FROM %s %s",
+			q.startQuery("SELECT"),
+			q.QuoteIdentifier(pkColumn),
+			q.QualifiedView(view),
+			q.pkTail("", conflictColumns, 1),
+		)
+		return q.scanRow([]interface{}{record.PKPointer()}, query, args...)
+
+	case Returning, OutputInserted:
+		if pkColumn != "" {
+			return q.scanRow([]interface{}{record.PKPointer()}, query, args...)
+		}
+		_, err = q.Exec(query, args...)
+		return err
+
+	default:
+		panic("reform: Unhandled LastInsertIdMethod. Please report this bug.")
+	}
+}
+
+// UpsertMulti inserts several structs into SQL database table with single query, updating existing rows
+// in case of conflict on conflictColumns. See Upsert for details about conflict and update columns.
+// If they implement BeforeInserter, it calls BeforeInsert() before doing so.
+// If they implement AfterInserter and AfterSaver, it calls AfterInsert() and AfterSave() after all queries.
+//
+// All structs should belong to the same view/table, and should not conflict with each other.
+// All records should either have or not have primary key set.
+// It doesn't fill primary key fields.
+//
+// If the number of placeholders exceeds dialect's limit, several queries are used like for InsertMulti.
+func (q *Querier) UpsertMulti(structs []Struct, conflictColumns []string, updateColumns ...string) error {
+	if len(structs) == 0 {
+		return nil
+	}
+
+	view := structs[0].View()
+	columns, values, err := q.multiColumnsAndValues("UpsertMulti", structs)
+	if err != nil {
+		return err
+	}
+	if updateColumns, err = upsertColumns(view, columns, conflictColumns, updateColumns); err != nil {
+		return err
+	}
+
+	err = q.multiChunks(structs, columns, values, func(structs []Struct, values []interface{}) error {
+		rows := q.valuesRows(len(columns), len(structs))
+		query := q.upsertQuery(view, columns, rows, conflictColumns, updateColumns, "")
+		_, err := q.Exec(query, q.upsertArgs(view, values)...)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, str := range structs {
+		takeSnapshot(str)
+		if e := q.afterUpsert(str); err == nil {
+			err = e
+		}
+	}
 	return err
 }
 
@@ -281,7 +1087,52 @@ func (q *Querier) update(str Struct, columns []string, values []interface{}, tai
 	return uint(ra), nil
 }
 
+func (q *Querier) afterUpdate(str Struct) error {
+	if au, ok := str.(AfterUpdaterContext); ok {
+		return au.AfterUpdateContext(q.ctx, q)
+	}
+	if au, ok := str.(AfterUpdater); ok {
+		return au.AfterUpdate()
+	}
+	return nil
+}
+
+func (q *Querier) afterSave(record Record) error {
+	if as, ok := record.(AfterSaverContext); ok {
+		return as.AfterSaveContext(q.ctx, q)
+	}
+	if as, ok := record.(AfterSaver); ok {
+		return as.AfterSave()
+	}
+	return nil
+}
+
+func (q *Querier) beforeDelete(record Record) error {
+	if bd, ok := record.(BeforeDeleterContext); ok {
+		return bd.BeforeDeleteContext(q.ctx, q)
+	}
+	if bd, ok := record.(BeforeDeleter); ok {
+		return bd.BeforeDelete()
+	}
+	return nil
+}
+
+func (q *Querier) afterDelete(record Record) error {
+	if ad, ok := record.(AfterDeleterContext); ok {
+		return ad.AfterDeleteContext(q.ctx, q)
+	}
+	if ad, ok := record.(AfterDeleter); ok {
+		return ad.AfterDelete()
+	}
+	return nil
+}
+
 func (q *Querier) beforeUpdate(str Struct) error {
+	q.setAutoUpdate(str)
+
+	if bu, ok := str.(BeforeUpdaterContext); ok {
+		return bu.BeforeUpdateContext(q.ctx, q)
+	}
 	if bu, ok := str.(BeforeUpdater); ok {
 		if err := bu.BeforeUpdate(); err != nil {
 			return err
@@ -291,10 +1142,61 @@ func (q *Querier) beforeUpdate(str Struct) error {
 	return nil
 }
 
+// updateByPK updates given columns of row specified by primary key (and version for VersionedTable)
+// with given values.
+func (q *Querier) updateByPK(record Record, columns []string, values []interface{}) error {
+	pkColumns, pkValues := pkColumnsAndValues(record)
+	lock := newVersionLock(record)
+	if lock != nil {
+		columns, values = lock.set(columns, values)
+		pkColumns, pkValues = lock.where(pkColumns, pkValues)
+	}
+	tail := q.pkTail("", pkColumns, len(columns)+1)
+	updated := append([]string(nil), columns...) // update quotes columns in place
+
+	ra, err := q.update(record, columns, values, tail, pkValues...)
+	if ra > 1 {
+		panic(fmt.Sprintf("reform: %d rows by UPDATE by primary key. Please report this bug.", ra))
+	}
+	if err != nil {
+		return err
+	}
+
+	if ra == 0 {
+		if lock == nil {
+			return ErrNoRows
+		}
+
+		// distinguish stale record from absent one
+		pkColumns, pkValues = pkColumnsAndValues(record)
+		count, err := q.WithDeleted().Count(record.Table(), q.pkTail("", pkColumns, 1), pkValues...)
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return ErrNoRows
+		}
+		return ErrStaleRecord
+	}
+
+	if lock != nil {
+		lock.apply()
+	}
+	updateSnapshot(record, updated)
+	return nil
+}
+
 // Update updates all columns of row specified by primary key in SQL database table with given record.
 // If record implements BeforeUpdater, it calls BeforeUpdate() before doing so.
+// If record implements AfterUpdater, it calls AfterUpdate() after that.
+// For AutoUpdateTable records, it sets autoupdate field to the current time before BeforeUpdate().
+// AutoCreateTable's autocreate column is never updated.
+//
+// For VersionedTable records, it also checks that version column value in SQL database matches record's one,
+// and increments it.
 //
 // Method returns ErrNoRows if no rows were updated.
+// Method returns ErrStaleRecord if row exists, but has a different version.
 // Method returns ErrNoPK if primary key is not set.
 func (q *Querier) Update(record Record) error {
 	if err := q.beforeUpdate(record); err != nil {
@@ -304,32 +1206,26 @@ func (q *Querier) Update(record Record) error {
 		return ErrNoPK
 	}
 
-	table := record.Table()
-	values := record.Values()
-	columns := table.Columns()
-
-	// cut primary key, make tail
-	pk := table.PKColumnIndex()
-	pkColumn := columns[pk]
-	values = append(values[:pk], values[pk+1:]...)
-	columns = append(columns[:pk], columns[pk+1:]...)
-	tail := fmt.Sprintf("WHERE %s = %s", q.QuoteIdentifier(pkColumn), q.Placeholder(len(columns)+1))
-
-	ra, err := q.update(record, columns, values, tail, record.PKValue())
-	if ra > 1 {
-		panic(fmt.Sprintf("reform: %d rows by UPDATE by primary key. Please report this bug.", ra))
-	}
-	if err == nil && ra == 0 {
-		err = ErrNoRows
+	// cut primary key and autocreate column
+	columns, values := withoutPK(record.Table(), record.Table().Columns(), record.Values())
+	columns, values = withoutAutoCreate(record.Table(), columns, values)
+	if err := q.updateByPK(record, columns, values); err != nil {
+		return err
 	}
-	return err
+	takeSnapshot(record)
+	return q.afterUpdate(record)
 }
 
 // UpdateColumns updates specified columns of row specified by primary key in SQL database table with given record.
 // Other columns are omitted from generated UPDATE statement.
 // If record implements BeforeUpdater, it calls BeforeUpdate() before doing so.
+// If record implements AfterUpdater, it calls AfterUpdate() after that.
+//
+// For VersionedTable records, it also checks and increments version column like Update,
+// even if it is not specified. The same is true for AutoUpdateTable's autoupdate column.
 //
 // Method returns ErrNoRows if no rows were updated.
+// Method returns ErrStaleRecord if row exists, but has a different version.
 // Method returns ErrNoPK if primary key is not set.
 func (q *Querier) UpdateColumns(record Record, columns ...string) error {
 	if err := q.beforeUpdate(record); err != nil {
@@ -345,29 +1241,83 @@ func (q *Querier) UpdateColumns(record Record, columns ...string) error {
 	}
 
 	if len(values) == 0 {
-		// TODO make exported type for that error
-		return fmt.Errorf("reform: nothing to update")
+		return ErrNothingToUpdate
+	}
+
+	columns, values = q.withAutoUpdate(record, columns, values)
+	if err = q.updateByPK(record, columns, values); err != nil {
+		return err
+	}
+	return q.afterUpdate(record)
+}
+
+// UpdateChanged updates columns of row specified by primary key in SQL database table with given record,
+// which were changed since record's Snapshot was taken (see ChangedColumns). Other columns are omitted
+// from generated UPDATE statement. If record doesn't embed Snapshot, or it was not taken yet,
+// it updates all columns like Update.
+// If record implements BeforeUpdater, it calls BeforeUpdate() before comparing values.
+// If record implements AfterUpdater, it calls AfterUpdate() after that.
+//
+// For VersionedTable records, it also checks and increments version column like Update.
+// The same is true for AutoUpdateTable's autoupdate column, but only if other columns were changed.
+//
+// Method returns ErrNothingToUpdate if no columns were changed.
+// Method returns ErrNoRows if no rows were updated.
+// Method returns ErrStaleRecord if row exists, but has a different version.
+// Method returns ErrNoPK if primary key is not set.
+func (q *Querier) UpdateChanged(record Record) error {
+	if err := q.beforeUpdate(record); err != nil {
+		return err
+	}
+	if !record.HasPK() {
+		return ErrNoPK
 	}
 
-	// make tail
+	// skip columns set by reform itself
 	table := record.Table()
-	pkColumn := table.Columns()[table.PKColumnIndex()]
-	tail := fmt.Sprintf("WHERE %s = %s", q.QuoteIdentifier(pkColumn), q.Placeholder(len(columns)+1))
+	skip := make(map[string]struct{})
+	for _, i := range table.PKColumnIndexes() {
+		skip[table.Columns()[i]] = struct{}{}
+	}
+	if t, ok := table.(VersionedTable); ok {
+		skip[table.Columns()[t.VersionColumnIndex()]] = struct{}{}
+	}
+	if t, ok := table.(AutoCreateTable); ok {
+		skip[table.Columns()[t.AutoCreateColumnIndex()]] = struct{}{}
+	}
+	if t, ok := table.(AutoUpdateTable); ok {
+		skip[table.Columns()[t.AutoUpdateColumnIndex()]] = struct{}{}
+	}
+	var columns []string
+	for _, c := range ChangedColumns(record) {
+		if _, ok := skip[c]; !ok {
+			columns = append(columns, c)
+		}
+	}
+	if len(columns) == 0 {
+		return ErrNothingToUpdate
+	}
 
-	ra, err := q.update(record, columns, values, tail, record.PKValue())
-	if ra > 1 {
-		panic(fmt.Sprintf("reform: %d rows by UPDATE by primary key. Please report this bug.", ra))
+	columns, values, err := filteredColumnsAndValues(record, columns, true)
+	if err != nil {
+		return err
 	}
-	if err == nil && ra == 0 {
-		err = ErrNoRows
+
+	columns, values = q.withAutoUpdate(record, columns, values)
+	if err = q.updateByPK(record, columns, values); err != nil {
+		return err
 	}
-	return err
+	takeSnapshot(record)
+	return q.afterUpdate(record)
 }
 
 // UpdateView updates specified columns of rows specified by tail and args in SQL database table with given struct,
 // and returns a number of updated rows.
 // Other columns are omitted from generated UPDATE statement.
+// Args may consist of a single TailExpression (see package where).
 // If struct implements BeforeUpdater, it calls BeforeUpdate() before doing so.
+// If struct implements AfterUpdater, it calls AfterUpdate() after that, even if no rows were updated.
+// For AutoUpdateTable, it also updates autoupdate column like UpdateColumns.
 //
 // Method never returns ErrNoRows.
 func (q *Querier) UpdateView(str Struct, columns []string, tail string, args ...interface{}) (uint, error) {
@@ -381,61 +1331,145 @@ func (q *Querier) UpdateView(str Struct, columns []string, tail string, args ...
 	}
 
 	if len(values) == 0 {
-		// TODO make exported type for that error
-		return 0, fmt.Errorf("reform: nothing to update")
+		return 0, ErrNothingToUpdate
 	}
 
-	return q.update(str, columns, values, tail, args...)
+	columns, values = q.withAutoUpdate(str, columns, values)
+	tail, args = q.expandTail(tail, args, len(columns)+1)
+	ra, err := q.update(str, columns, values, tail, args...)
+	if err != nil {
+		return 0, err
+	}
+	return ra, q.afterUpdate(str)
+}
+
+// withAutoUpdate returns columns and values with added AutoUpdateTable's autoupdate column
+// and struct's field value already set by beforeUpdate.
+func (q *Querier) withAutoUpdate(str Struct, columns []string, values []interface{}) ([]string, []interface{}) {
+	table, ok := str.View().(AutoUpdateTable)
+	if !ok {
+		return columns, values
+	}
+
+	i := table.AutoUpdateColumnIndex()
+	return setColumn(columns, values, table.Columns()[i], str.Values()[i])
 }
 
 // Save saves record in SQL database table.
 // If primary key is set, it first calls Update and checks if row was affected (matched).
 // If primary key is absent or no row was affected, it calls Insert. This allows to call Save with Record
-// with primary key set.
+// with primary key set. For VersionedTable records, it returns ErrStaleRecord from Update.
+// If record implements AfterSaver, it calls AfterSave() after successful Update or Insert
+// (and their AfterUpdate() or AfterInsert()).
 func (q *Querier) Save(record Record) error {
 	if record.HasPK() {
 		err := q.Update(record)
 		if err != ErrNoRows {
-			return err
+			if err != nil {
+				return err
+			}
+			return q.afterSave(record)
 		}
 	}
 
-	return q.Insert(record)
+	if err := q.Insert(record); err != nil {
+		return err
+	}
+	return q.afterSave(record)
 }
 
 // SaveColumns saves record specific columns in SQL database table.
 // If primary key is set, it first calls UpdateColumns and checks if row was updated.
 // If primary key is absent or no row was updated, it calls Insert.
+// If record implements AfterSaver, it calls AfterSave() like Save.
 func (q *Querier) SaveColumns(record Record, columns ...string) error {
 	if record.HasPK() {
 		err := q.UpdateColumns(record, columns...)
 		if err != ErrNoRows {
-			return err
+			if err != nil {
+				return err
+			}
+			return q.afterSave(record)
 		}
 	}
 
-	return q.Insert(record)
+	if err := q.Insert(record); err != nil {
+		return err
+	}
+	return q.afterSave(record)
 }
 
 // Delete deletes record from SQL database table by primary key.
+// If record implements BeforeDeleter, it calls BeforeDelete() before doing so.
+// If record implements AfterDeleter, it calls AfterDelete() after that.
+//
+// For SoftDeleteTable records, it sets soft delete column and record's field to the current time instead,
+// if it was not set yet. Use HardDelete to delete such records.
 //
 // Method returns ErrNoRows if no rows were deleted.
 // Method returns ErrNoPK if primary key is not set.
 func (q *Querier) Delete(record Record) error {
+	table, ok := record.Table().(SoftDeleteTable)
+	if !ok {
+		return q.HardDelete(record)
+	}
+
+	if err := q.beforeDelete(record); err != nil {
+		return err
+	}
 	if !record.HasPK() {
 		return ErrNoPK
 	}
 
-	table := record.Table()
-	pk := table.PKColumnIndex()
-	query := fmt.Sprintf("%s FROM %s WHERE %s = %s",
-		q.startQuery("DELETE"),
+	i := table.SoftDeleteColumnIndex()
+	column := q.QuoteIdentifier(table.Columns()[i])
+	now := q.now()
+	pkColumns, pkValues := pkColumnsAndValues(record)
+	query := fmt.Sprintf("%s %s SET %s = %s %s AND %s IS NULL",
+		q.startQuery("UPDATE"),
 		q.QualifiedView(table),
-		q.QuoteIdentifier(table.Columns()[pk]),
-		q.Placeholder(1),
+		column, q.Placeholder(1),
+		q.pkTail("", pkColumns, 2),
+		column,
 	)
 
-	res, err := q.Exec(query, record.PKValue())
+	if err := q.execByPK("UPDATE", query, append([]interface{}{now}, pkValues...)); err != nil {
+		return err
+	}
+	setTime(record.Pointers()[i], now)
+	return q.afterDelete(record)
+}
+
+// HardDelete deletes record from SQL database table by primary key.
+// Unlike Delete, it always uses DELETE statement, even for SoftDeleteTable records.
+// It calls BeforeDelete() and AfterDelete() like Delete.
+//
+// Method returns ErrNoRows if no rows were deleted.
+// Method returns ErrNoPK if primary key is not set.
+func (q *Querier) HardDelete(record Record) error {
+	if err := q.beforeDelete(record); err != nil {
+		return err
+	}
+	if !record.HasPK() {
+		return ErrNoPK
+	}
+
+	pkColumns, pkValues := pkColumnsAndValues(record)
+	query := fmt.Sprintf("%s FROM %s %s",
+		q.startQuery("DELETE"),
+		q.QualifiedView(record.Table()),
+		q.pkTail("", pkColumns, 1),
+	)
+
+	if err := q.execByPK("DELETE", query, pkValues); err != nil {
+		return err
+	}
+	return q.afterDelete(record)
+}
+
+// execByPK executes given UPDATE or DELETE command query by primary key and checks the number of affected rows.
+func (q *Querier) execByPK(command string, query string, args []interface{}) error {
+	res, err := q.Exec(query, args...)
 	if err != nil {
 		return err
 	}
@@ -447,21 +1481,65 @@ func (q *Querier) Delete(record Record) error {
 		return ErrNoRows
 	}
 	if ra > 1 {
-		panic(fmt.Sprintf("reform: %d rows by DELETE by primary key. Please report this bug.", ra))
+		panic(fmt.Sprintf("reform: %d rows by %s by primary key. Please report this bug.", ra, command))
 	}
 	return nil
 }
 
 // DeleteFrom deletes rows from view with tail and args and returns a number of deleted rows.
+// Args may consist of a single TailExpression (see package where).
+//
+// For SoftDeleteTable, it sets soft delete column to the current time instead for rows which
+// are not soft-deleted yet, and returns their number. Use HardDeleteFrom to delete rows of such tables.
 //
 // Method never returns ErrNoRows.
 func (q *Querier) DeleteFrom(view View, tail string, args ...interface{}) (uint, error) {
+	tail, args = q.expandTail(tail, args, 1)
+	table, ok := view.(SoftDeleteTable)
+	if !ok {
+		return q.HardDeleteFrom(view, tail, args...)
+	}
+
+	// current time placeholder is before tail in query text, and should be before tail args for dialects
+	// with positional placeholders, and after them for dialects with numbered placeholders
+	var placeholder string
+	if q.Placeholder(1) == q.Placeholder(2) {
+		placeholder = q.Placeholder(1)
+		args = append([]interface{}{q.now()}, args...)
+	} else {
+		placeholder = q.Placeholder(len(args) + 1)
+		args = append(args, q.now())
+	}
+
+	column := q.QuoteIdentifier(table.Columns()[table.SoftDeleteColumnIndex()])
+	query := fmt.Sprintf("%s %s SET %s = %s %s",
+		q.startQuery("UPDATE"),
+		q.QualifiedView(table),
+		column, placeholder,
+		addCond(tail, column+" IS NULL"),
+	)
+
+	return q.execRows(query, args)
+}
+
+// HardDeleteFrom deletes rows from view with tail and args and returns a number of deleted rows.
+// Args may consist of a single TailExpression (see package where).
+// Unlike DeleteFrom, it always uses DELETE statement, even for SoftDeleteTable.
+//
+// Method never returns ErrNoRows.
+func (q *Querier) HardDeleteFrom(view View, tail string, args ...interface{}) (uint, error) {
+	tail, args = q.expandTail(tail, args, 1)
 	query := fmt.Sprintf("%s FROM %s %s",
 		q.startQuery("DELETE"),
 		q.QualifiedView(view),
 		tail,
 	)
 
+	return q.execRows(query, args)
+}
+
+// execRows executes given command query and returns a number of affected rows.
+func (q *Querier) execRows(query string, args []interface{}) (uint, error) {
 	res, err := q.Exec(query, args...)
 	if err != nil {
 		return 0, err
@@ -1,14 +1,19 @@
 package reform_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"testing"
 	"time"
 
 	"github.com/AlekSi/pointer"
 	"github.com/brianvoe/gofakeit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/mc2soft/reform"
+	"github.com/mc2soft/reform/dialects/mysql"
 	"github.com/mc2soft/reform/dialects/postgresql"
 	. "github.com/mc2soft/reform/internal/test/models"
 )
@@ -147,17 +152,82 @@ func (s *ReformSuite) TestInsertMulti() {
 	err := s.q.InsertMulti(person1, person2)
 	s.NoError(err)
 
-	s.Equal(int32(0), person1.ID)
 	s.Equal("", person1.Name)
 	s.Equal(&newEmail, person1.Email)
 	s.WithinDuration(time.Now(), person1.CreatedAt, 2*time.Second)
 	s.Nil(person1.UpdatedAt)
 
-	s.Equal(int32(0), person2.ID)
 	s.Equal(newName, person2.Name)
 	s.Nil(person2.Email)
 	s.WithinDuration(time.Now(), person2.CreatedAt, 2*time.Second)
 	s.Nil(person2.UpdatedAt)
+
+	// MySQL fills primary keys only for some server settings
+	if s.q.Dialect == mysql.Dialect && person1.ID == 0 {
+		s.Equal(int32(0), person2.ID)
+		return
+	}
+
+	s.NotEqual(int32(0), person1.ID)
+	s.Equal(person1.ID+1, person2.ID)
+
+	person, err := s.q.FindByPrimaryKeyFrom(PersonTable, person1.ID)
+	s.NoError(err)
+	s.Equal(person1, person)
+
+	person, err = s.q.FindByPrimaryKeyFrom(PersonTable, person2.ID)
+	s.NoError(err)
+	s.Equal(person2, person)
+}
+
+func (s *ReformSuite) TestInsertMultiOrder() {
+	persons := make([]reform.Struct, 10)
+	for i := range persons {
+		persons[i] = &Person{Name: fmt.Sprintf("InsertMultiOrder %d", i)}
+	}
+	err := s.q.InsertMulti(persons...)
+	s.NoError(err)
+
+	for _, str := range persons {
+		person := str.(*Person)
+		if s.q.Dialect == mysql.Dialect && person.ID == 0 {
+			continue
+		}
+
+		p, err := s.q.FindByPrimaryKeyFrom(PersonTable, person.ID)
+		s.NoError(err)
+		s.Equal(person.Name, p.(*Person).Name)
+	}
+}
+
+func (s *ReformSuite) TestInsertMultiBatches() {
+	persons := make([]reform.Struct, 5)
+	for i := range persons {
+		persons[i] = &Person{Name: fmt.Sprintf("InsertMultiBatches %d", i)}
+	}
+	for start := 0; start < len(persons); start += 3 {
+		end := start + 3
+		if end > len(persons) {
+			end = len(persons)
+		}
+		s.NoError(s.q.InsertMulti(persons[start:end]...))
+	}
+
+	if s.q.Dialect == mysql.Dialect && persons[0].(*Person).ID == 0 {
+		s.T().Skip("MySQL server settings do not allow to fill primary keys")
+	}
+
+	ids := make(map[int32]struct{}, len(persons))
+	for _, str := range persons {
+		person := str.(*Person)
+		s.NotEqual(int32(0), person.ID)
+		s.NotContains(ids, person.ID)
+		ids[person.ID] = struct{}{}
+
+		p, err := s.q.FindByPrimaryKeyFrom(PersonTable, person.ID)
+		s.NoError(err)
+		s.Equal(person, p)
+	}
 }
 
 func (s *ReformSuite) TestInsertMultiWithPrimaryKeys() {
@@ -202,6 +272,177 @@ func (s *ReformSuite) TestInsertMultiMixes() {
 	s.Error(err)
 }
 
+// placeholdersLimitDialect overrides dialect's placeholders limit.
+type placeholdersLimitDialect struct {
+	reform.Dialect
+	limit int
+}
+
+func (d placeholdersLimitDialect) MaxPlaceholders() int {
+	return d.limit
+}
+
+func (s *ReformSuite) TestInsertMultiChunks() {
+	q := s.q.WithTag("test:%s", s.T().Name())
+	q.Dialect = placeholdersLimitDialect{Dialect: s.q.Dialect, limit: 12}
+
+	persons := make([]reform.Struct, 5)
+	for i := range persons {
+		persons[i] = &Person{Name: fmt.Sprintf("InsertMultiChunks %d", i)}
+	}
+	err := q.InsertMulti(persons...)
+	s.NoError(err)
+
+	ids := make([]interface{}, len(persons))
+	for i, str := range persons {
+		ids[i] = str.(*Person).ID
+	}
+	if s.q.Dialect == mysql.Dialect && ids[0] == int32(0) {
+		s.T().Skip("MySQL server settings do not allow to fill primary keys")
+	}
+
+	structs, err := q.FindAllFrom(PersonTable, "id", ids...)
+	s.NoError(err)
+	s.ElementsMatch(persons, structs)
+
+	q.Dialect = placeholdersLimitDialect{Dialect: s.q.Dialect, limit: 3}
+	err = q.InsertMulti(&Person{}, &Person{})
+	s.EqualError(err, "reform: 5 columns exceed the limit of 3 placeholders")
+}
+
+func (s *ReformSuite) TestUpsertMultiChunks() {
+	q := s.q.WithTag("test:%s", s.T().Name())
+	q.Dialect = placeholdersLimitDialect{Dialect: s.q.Dialect, limit: 8}
+
+	start := time.Now().UTC().Truncate(24 * time.Hour)
+	projects := make([]reform.Struct, 5)
+	ids := make([]interface{}, len(projects))
+	for i := range projects {
+		id := fmt.Sprintf("chunk%d", i)
+		projects[i] = &Project{ID: id, Name: id, Start: start}
+		ids[i] = id
+	}
+	err := q.UpsertMulti(projects, []string{"id"})
+	s.NoError(err)
+
+	structs, err := q.FindAllFrom(ProjectTable, "id", ids...)
+	s.NoError(err)
+	s.ElementsMatch(projects, structs)
+}
+
+func (s *ReformSuite) TestUpdateVersion() {
+	doc := &Document{Name: "draft"}
+	s.Require().NoError(s.q.Insert(doc))
+	s.Equal(int64(0), doc.LockVersion)
+
+	doc.Name = "first"
+	s.NoError(s.q.Update(doc))
+	s.Equal(int64(1), doc.LockVersion)
+
+	stale := &Document{ID: doc.ID, Name: "stale", LockVersion: 0}
+	s.Equal(reform.ErrStaleRecord, s.q.Update(stale))
+	s.Equal(int64(0), stale.LockVersion)
+	s.Equal(reform.ErrStaleRecord, s.q.UpdateColumns(stale, "name"))
+	s.Equal(reform.ErrStaleRecord, s.q.Save(stale))
+
+	doc.Name = "second"
+	s.NoError(s.q.UpdateColumns(doc, "name"))
+	s.Equal(int64(2), doc.LockVersion)
+
+	doc.Name = "third"
+	s.NoError(s.q.Save(doc))
+	s.Equal(int64(3), doc.LockVersion)
+
+	doc2, err := s.q.FindByPrimaryKeyFrom(DocumentTable, doc.ID)
+	s.NoError(err)
+	s.Equal(doc, doc2)
+
+	absent := &Document{ID: doc.ID + 1000, Name: "absent"}
+	s.Equal(reform.ErrNoRows, s.q.Update(absent))
+	s.Equal(int64(0), absent.LockVersion)
+}
+
+func (s *ReformSuite) TestSoftDelete() {
+	doc := &Document{Name: "soft"}
+	s.Require().NoError(s.q.Insert(doc))
+
+	s.NoError(s.q.Delete(doc))
+	s.Require().NotNil(doc.DeletedAt)
+	s.WithinDuration(time.Now(), *doc.DeletedAt, 2*time.Second)
+	s.Equal(reform.ErrNoRows, s.q.Delete(doc))
+
+	_, err := s.q.FindByPrimaryKeyFrom(DocumentTable, doc.ID)
+	s.Equal(reform.ErrNoRows, err)
+	count, err := s.q.Count(DocumentTable, "WHERE id = "+s.q.Placeholder(1), doc.ID)
+	s.NoError(err)
+	s.Equal(0, count)
+	structs, err := s.q.FindAllFrom(DocumentTable, "id", doc.ID)
+	s.NoError(err)
+	s.Empty(structs)
+
+	doc2, err := s.q.WithDeleted().FindByPrimaryKeyFrom(DocumentTable, doc.ID)
+	s.NoError(err)
+	s.NotNil(doc2.(*Document).DeletedAt)
+
+	s.NoError(s.q.HardDelete(doc))
+	_, err = s.q.WithDeleted().FindByPrimaryKeyFrom(DocumentTable, doc.ID)
+	s.Equal(reform.ErrNoRows, err)
+	s.Equal(reform.ErrNoRows, s.q.HardDelete(doc))
+}
+
+func (s *ReformSuite) TestSoftDeleteFrom() {
+	doc1, doc2 := &Document{Name: "soft1"}, &Document{Name: "soft2"}
+	s.Require().NoError(s.q.Insert(doc1))
+	s.Require().NoError(s.q.Insert(doc2))
+
+	tail := fmt.Sprintf("WHERE name = %s AND id = %s", s.q.Placeholder(1), s.q.Placeholder(2))
+	ra, err := s.q.DeleteFrom(DocumentTable, tail, doc1.Name, doc1.ID)
+	s.NoError(err)
+	s.Equal(uint(1), ra)
+
+	structs, err := s.q.SelectAllFrom(DocumentTable, "WHERE documents.name LIKE "+s.q.Placeholder(1), "soft%")
+	s.NoError(err)
+	s.Equal([]reform.Struct{doc2}, structs)
+
+	// condition is added to the existing WHERE clause and combined with it correctly
+	name := s.q.QualifiedView(DocumentTable) + "." + s.q.QuoteIdentifier("name")
+	tail = fmt.Sprintf("WHERE %s = %s OR (%s = %s) ORDER BY id", name, s.q.Placeholder(1), name, s.q.Placeholder(2))
+	structs, err = s.q.SelectAllFrom(DocumentTable, tail, doc1.Name, doc2.Name)
+	s.NoError(err)
+	s.Equal([]reform.Struct{doc2}, structs)
+
+	count, err := s.q.Count(DocumentTable, "WHERE id IN (SELECT id FROM documents WHERE name LIKE 'soft%')")
+	s.NoError(err)
+	s.Equal(1, count)
+
+	for _, tail := range []string{"-- WHERE", "-- WHERE\nORDER BY 1", "WHERE id > 0 -- comment\nORDER BY 1"} {
+		count, err = s.q.Count(DocumentTable, tail)
+		s.NoError(err)
+		s.Equal(1, count, "%q", tail)
+	}
+
+	structs, err = s.q.WithDeleted().SelectAllFrom(DocumentTable, "ORDER BY id")
+	s.NoError(err)
+	s.Require().Len(structs, 2)
+	s.NotNil(structs[0].(*Document).DeletedAt)
+	s.Nil(structs[1].(*Document).DeletedAt)
+
+	// already soft-deleted rows are neither updated nor counted
+	deletedAt := *structs[0].(*Document).DeletedAt
+	for _, expected := range []uint{1, 0} {
+		ra, err = s.q.DeleteFrom(DocumentTable, "WHERE name LIKE "+s.q.Placeholder(1), "soft%")
+		s.NoError(err)
+		s.Equal(expected, ra)
+	}
+	s.NoError(s.q.WithDeleted().Reload(doc1))
+	s.Require().NotNil(doc1.DeletedAt)
+	s.True(deletedAt.Equal(*doc1.DeletedAt), "%s != %s", deletedAt, doc1.DeletedAt)
+
+	ra, err = s.q.HardDeleteFrom(DocumentTable, "")
+	s.NoError(err)
+	s.Equal(uint(2), ra)
+}
+
 func (s *ReformSuite) TestInsertIDOnly() {
 	var id IDOnly
 	err := s.q.Insert(&id)
@@ -209,6 +450,114 @@ func (s *ReformSuite) TestInsertIDOnly() {
 	s.Equal(int32(1), id.ID)
 }
 
+func (s *ReformSuite) TestUpsert() {
+	start := time.Now().UTC().Truncate(24 * time.Hour)
+	project := &Project{ID: "new", Name: "New", Start: start}
+	err := s.q.Upsert(project, []string{"id"})
+	s.NoError(err)
+
+	project2, err := s.q.FindByPrimaryKeyFrom(ProjectTable, "new")
+	s.NoError(err)
+	s.Equal(project, project2)
+
+	project.Name = "Updated"
+	project.Start = start.AddDate(0, 0, -1)
+	err = s.q.Upsert(project, []string{"id"}, "name")
+	s.NoError(err)
+
+	project2, err = s.q.FindByPrimaryKeyFrom(ProjectTable, "new")
+	s.NoError(err)
+	s.Equal("Updated", project2.(*Project).Name)
+	s.Equal(start, project2.(*Project).Start)
+
+	project.Name = "Updated again"
+	err = s.q.Upsert(project, []string{"id"})
+	s.NoError(err)
+
+	project2, err = s.q.FindByPrimaryKeyFrom(ProjectTable, "new")
+	s.NoError(err)
+	s.Equal(project, project2)
+
+	count, err := s.q.Count(ProjectTable, "")
+	s.NoError(err)
+	s.Equal(6, count)
+}
+
+func (s *ReformSuite) TestUpsertWithPrimaryKey() {
+	newName := gofakeit.Name()
+	person := &Person{ID: 102, Name: newName}
+	withIdentityInsert(s.T(), s.q, "people", func() {
+		err := s.q.Upsert(person, []string{"id"}, "name")
+		s.NoError(err)
+	})
+	s.Equal(int32(102), person.ID)
+
+	person2, err := s.q.FindByPrimaryKeyFrom(PersonTable, int32(102))
+	s.NoError(err)
+	s.Equal(newName, person2.(*Person).Name)
+	s.Equal(pointer.ToString("elfrieda_abbott@example.org"), person2.(*Person).Email)
+	s.Equal(personCreated, person2.(*Person).CreatedAt)
+}
+
+func (s *ReformSuite) TestUpsertCompositePK() {
+	cpk := &CompositePK{I: 1, Name: "first", ID: "one"}
+	err := s.q.Upsert(cpk, []string{"i", "id"})
+	s.NoError(err)
+
+	// nothing to update except conflict columns
+	err = s.q.Upsert(cpk, []string{"i", "id"}, "name")
+	s.NoError(err)
+
+	cpk.Name = "updated"
+	err = s.q.Upsert(cpk, []string{"i", "id"})
+	s.NoError(err)
+
+	cpk2, err := s.q.FindByPrimaryKeyFrom(CompositePKTable, cpk.PKValue())
+	s.NoError(err)
+	s.Equal(cpk, cpk2)
+}
+
+func (s *ReformSuite) TestUpsertMulti() {
+	err := s.q.UpsertMulti(nil, []string{"id"})
+	s.NoError(err)
+
+	start := time.Now().UTC().Truncate(24 * time.Hour)
+	project1 := &Project{ID: "baron", Name: "Updated Baron", Start: start}
+	project2 := &Project{ID: "new", Name: "New", Start: start}
+	err = s.q.UpsertMulti([]reform.Struct{project1, project2}, []string{"id"}, "name")
+	s.NoError(err)
+
+	projects, err := s.q.FindAllFrom(ProjectTable, "id", "baron", "new")
+	s.NoError(err)
+	s.Require().Len(projects, 2)
+	for _, p := range projects {
+		switch p := p.(*Project); p.ID {
+		case "baron":
+			s.Equal("Updated Baron", p.Name)
+			s.Equal(baronStart, p.Start)
+		case "new":
+			s.Equal(project2, p)
+		default:
+			s.Fail("unexpected project", "%s", p)
+		}
+	}
+
+	err = s.q.UpsertMulti([]reform.Struct{project1, new(Person)}, []string{"id"})
+	s.EqualError(err, "reform: different tables in UpsertMulti: projects and people")
+}
+
+func (s *ReformSuite) TestUpsertErrors() {
+	project := &Project{ID: "new", Name: "New", Start: time.Now().UTC().Truncate(24 * time.Hour)}
+	for e, args := range map[error][2][]string{
+		reform.ErrNoConflictColumns:                                     {nil, nil},
+		&reform.UnexpectedColumnsError{Columns: []string{"foo", "bar"}}: {{"foo"}, {"bar"}},
+		&reform.PKColumnUpdateError{Column: "id"}:                       {{"name"}, {"id"}},
+	} {
+		err := s.q.Upsert(project, args[0], args[1]...)
+		s.Equal(e, err)
+	}
+}
+
 func (s *ReformSuite) TestUpdate() {
 	var person Person
 	err := s.q.Update(&person)
@@ -283,9 +632,9 @@ func (s *ReformSuite) TestUpdateColumns() {
 
 	person := &Person{ID: 102, Name: newName, Email: &newEmail, CreatedAt: personCreated}
 	for e, columns := range map[error][]string{
-		errors.New("reform: unexpected columns: [foo]"):     {"foo"},
-		errors.New("reform: will not update PK column: id"): {"id"},
-		errors.New("reform: nothing to update"):             {},
+		&reform.UnexpectedColumnsError{Columns: []string{"foo"}}: {"foo"},
+		&reform.PKColumnUpdateError{Column: "id"}:                {"id"},
+		reform.ErrNothingToUpdate:                                {},
 	} {
 		err := s.q.UpdateColumns(person, columns...)
 		s.Error(err)
@@ -328,9 +677,9 @@ func (s *ReformSuite) TestUpdateView() {
 
 	person := &Person{ID: 102, Name: newName, Email: &newEmail, CreatedAt: personCreated}
 	for e, columns := range map[error][]string{
-		errors.New("reform: unexpected columns: [foo]"):     {"foo"},
-		errors.New("reform: will not update PK column: id"): {"id"},
-		errors.New("reform: nothing to update"):             {},
+		&reform.UnexpectedColumnsError{Columns: []string{"foo"}}: {"foo"},
+		&reform.PKColumnUpdateError{Column: "id"}:                {"id"},
+		reform.ErrNothingToUpdate:                                {},
 	} {
 		ra, err := s.q.UpdateView(person, columns, "")
 		s.Error(err)
@@ -435,3 +784,410 @@ func (s *ReformSuite) TestCommandsSchema() {
 	err = s.q.Delete(legacyPerson)
 	s.NoError(err)
 }
+
+func (s *ReformSuite) TestCommandsCompositePK() {
+	cpk := &CompositePK{I: 1, Name: "first", ID: "one"}
+	s.True(cpk.HasPK())
+	s.Equal([]interface{}{int32(1), "one"}, cpk.PKValue())
+	s.Equal([]uint{0, 2}, CompositePKTable.PKColumnIndexes())
+
+	err := s.q.Insert(cpk)
+	s.NoError(err)
+	s.Equal(&CompositePK{I: 1, Name: "first", ID: "one"}, cpk)
+
+	err = s.q.Insert(cpk)
+	s.Error(err)
+
+	err = s.q.Insert(&CompositePK{I: 1, Name: "second", ID: "two"})
+	s.NoError(err)
+
+	cpk.Name = "updated"
+	err = s.q.Update(cpk)
+	s.NoError(err)
+
+	cpk2, err := s.q.FindByPrimaryKeyFrom(CompositePKTable, []interface{}{int32(1), "one"})
+	s.NoError(err)
+	s.Equal(cpk, cpk2)
+
+	_, err = s.q.FindByPrimaryKeyFrom(CompositePKTable, int32(1))
+	s.Error(err)
+
+	err = s.q.UpdateColumns(cpk, "id")
+	s.Equal(&reform.PKColumnUpdateError{Column: "id"}, err)
+
+	cpk3 := &CompositePK{I: 1, ID: "two"}
+	err = s.q.Reload(cpk3)
+	s.NoError(err)
+	s.Equal("second", cpk3.Name)
+
+	cpk3.Name = "saved"
+	err = s.q.Save(cpk3)
+	s.NoError(err)
+
+	cpk4 := &CompositePK{I: 2, Name: "third", ID: "one"}
+	err = s.q.Save(cpk4)
+	s.NoError(err)
+
+	count, err := s.q.Count(CompositePKTable, "")
+	s.NoError(err)
+	s.Equal(3, count)
+
+	err = s.q.Delete(cpk)
+	s.NoError(err)
+	err = s.q.Reload(cpk)
+	s.Equal(reform.ErrNoRows, err)
+	err = s.q.Delete(cpk)
+	s.Equal(reform.ErrNoRows, err)
+
+	err = s.q.Delete(&CompositePK{I: 1})
+	s.Equal(reform.ErrNoRows, err)
+	err = s.q.Delete(new(CompositePK))
+	s.Equal(reform.ErrNoPK, err)
+
+	// zero value is a valid part of composite primary key
+	zero := &CompositePK{Name: "zero", ID: "zero"}
+	s.True(zero.HasPK())
+	s.NoError(s.q.Insert(zero))
+	zero.Name = "updated zero"
+	s.NoError(s.q.Update(zero))
+	zero2 := &CompositePK{ID: "zero"}
+	s.NoError(s.q.Reload(zero2))
+	s.Equal(zero, zero2)
+
+	cpk5 := new(CompositePK)
+	cpk5.SetPK([]interface{}{2, "one"})
+	err = s.q.Reload(cpk5)
+	s.NoError(err)
+	s.Equal(cpk4, cpk5)
+}
+
+// hookedDocument records calls of lifecycle hooks with Document's state at the time of call.
+type hookedDocument struct {
+	reform.Record
+	calls []string
+	err   error // returned by BeforeDelete and AfterInsert
+}
+
+func (h *hookedDocument) call(hook string) {
+	doc := h.Record.(*Document)
+	h.calls = append(h.calls, fmt.Sprintf("%s pk=%t version=%d deleted=%t", hook, doc.HasPK(), doc.LockVersion, doc.DeletedAt != nil))
+}
+
+func (h *hookedDocument) AfterInsert() error  { h.call("AfterInsert"); return h.err }
+func (h *hookedDocument) AfterUpdate() error  { h.call("AfterUpdate"); return nil }
+func (h *hookedDocument) AfterSave() error    { h.call("AfterSave"); return nil }
+func (h *hookedDocument) BeforeDelete() error { h.call("BeforeDelete"); return h.err }
+func (h *hookedDocument) AfterDelete() error  { h.call("AfterDelete"); return nil }
+
+// check interfaces
+var (
+	_ reform.AfterInserter = (*hookedDocument)(nil)
+	_ reform.AfterUpdater  = (*hookedDocument)(nil)
+	_ reform.AfterSaver    = (*hookedDocument)(nil)
+	_ reform.BeforeDeleter = (*hookedDocument)(nil)
+	_ reform.AfterDeleter  = (*hookedDocument)(nil)
+)
+
+func TestHooks(t *testing.T) {
+	db, tx := setupTX(t)
+	defer teardown(t, db)
+	defer func() {
+		require.NoError(t, tx.Rollback())
+	}()
+
+	newDoc := func() *hookedDocument {
+		return &hookedDocument{Record: &Document{Name: "hooks"}}
+	}
+
+	t.Run("InsertUpdateDelete", func(t *testing.T) {
+		h := newDoc()
+		require.NoError(t, tx.Insert(h))
+		require.NoError(t, tx.Update(h))
+		require.NoError(t, tx.UpdateColumns(h, "name"))
+		require.NoError(t, tx.Delete(h))
+		require.NoError(t, tx.HardDelete(h))
+		assert.Equal(t, []string{
+			"AfterInsert pk=true version=0 deleted=false",
+			"AfterUpdate pk=true version=1 deleted=false",
+			"AfterUpdate pk=true version=2 deleted=false",
+			"BeforeDelete pk=true version=2 deleted=false",
+			"AfterDelete pk=true version=2 deleted=true",
+			"BeforeDelete pk=true version=2 deleted=true",
+			"AfterDelete pk=true version=2 deleted=true",
+		}, h.calls)
+	})
+
+	t.Run("Columns", func(t *testing.T) {
+		h := newDoc()
+		require.NoError(t, tx.InsertColumns(h, "name", "lock_version"))
+		_, err := tx.UpdateView(h, []string{"name"}, "WHERE id = "+tx.Placeholder(2), h.Record.(*Document).ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"AfterInsert pk=true version=0 deleted=false",
+			"AfterUpdate pk=true version=0 deleted=false",
+		}, h.calls)
+	})
+
+	t.Run("Save", func(t *testing.T) {
+		h := newDoc()
+		require.NoError(t, tx.Save(h))
+		require.NoError(t, tx.Save(h))
+		require.NoError(t, tx.SaveColumns(h, "name"))
+		assert.Equal(t, []string{
+			"AfterInsert pk=true version=0 deleted=false",
+			"AfterSave pk=true version=0 deleted=false",
+			"AfterUpdate pk=true version=1 deleted=false",
+			"AfterSave pk=true version=1 deleted=false",
+			"AfterUpdate pk=true version=2 deleted=false",
+			"AfterSave pk=true version=2 deleted=false",
+		}, h.calls)
+	})
+
+	t.Run("InsertMulti", func(t *testing.T) {
+		h1, h2 := newDoc(), newDoc()
+		require.NoError(t, tx.InsertMulti(h1, h2))
+		for _, h := range []*hookedDocument{h1, h2} {
+			assert.Equal(t, []string{"AfterInsert pk=true version=0 deleted=false"}, h.calls)
+		}
+	})
+
+	t.Run("Upsert", func(t *testing.T) {
+		h := newDoc()
+		require.NoError(t, tx.Insert(h))
+		h.calls = nil
+
+		withIdentityInsert(t, tx.Querier, "documents", func() {
+			require.NoError(t, tx.Upsert(h, []string{"id"}, "name"))
+			require.NoError(t, tx.UpsertMulti([]reform.Struct{h}, []string{"id"}, "name"))
+		})
+		assert.Equal(t, []string{
+			"AfterInsert pk=true version=0 deleted=false",
+			"AfterSave pk=true version=0 deleted=false",
+			"AfterInsert pk=true version=0 deleted=false",
+			"AfterSave pk=true version=0 deleted=false",
+		}, h.calls)
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		h := newDoc()
+		h.err = errors.New("epic error")
+		assert.Equal(t, h.err, tx.Insert(h))
+		assert.Equal(t, h.err, tx.Delete(h))
+		assert.Equal(t, h.err, tx.HardDelete(h))
+		assert.Equal(t, []string{
+			"AfterInsert pk=true version=0 deleted=false",
+			"BeforeDelete pk=true version=0 deleted=false",
+			"BeforeDelete pk=true version=0 deleted=false",
+		}, h.calls)
+
+		// record is inserted, but not deleted
+		doc := h.Record.(*Document)
+		assert.NoError(t, tx.Reload(doc))
+		assert.Nil(t, doc.DeletedAt)
+	})
+}
+
+// contextDocument records calls of context-aware lifecycle hooks, and checks their arguments.
+// It also implements hooks without context via embedded hookedDocument.
+type contextDocument struct {
+	*hookedDocument
+	t *testing.T
+}
+
+func (c *contextDocument) call(ctx context.Context, q *reform.Querier, hook string) error {
+	assert.Equal(c.t, "hooks", ctx.Value(ctxKey("k")), "%s", hook)
+	assert.Equal(c.t, ctx, q.Context(), "%s", hook)
+	assert.True(c.t, q.IsInTransaction(), "%s", hook)
+
+	// make a query in the same transaction
+	count, err := q.Count(DocumentTable, "")
+	if err != nil {
+		return err
+	}
+	c.hookedDocument.calls = append(c.hookedDocument.calls, fmt.Sprintf("%s documents=%d", hook, count))
+	return nil
+}
+
+func (c *contextDocument) BeforeInsertContext(ctx context.Context, q *reform.Querier) error {
+	return c.call(ctx, q, "BeforeInsertContext")
+}
+
+func (c *contextDocument) AfterInsertContext(ctx context.Context, q *reform.Querier) error {
+	return c.call(ctx, q, "AfterInsertContext")
+}
+
+func (c *contextDocument) BeforeUpdateContext(ctx context.Context, q *reform.Querier) error {
+	return c.call(ctx, q, "BeforeUpdateContext")
+}
+
+func (c *contextDocument) AfterUpdateContext(ctx context.Context, q *reform.Querier) error {
+	return c.call(ctx, q, "AfterUpdateContext")
+}
+
+func (c *contextDocument) AfterSaveContext(ctx context.Context, q *reform.Querier) error {
+	return c.call(ctx, q, "AfterSaveContext")
+}
+
+func (c *contextDocument) BeforeDeleteContext(ctx context.Context, q *reform.Querier) error {
+	return c.call(ctx, q, "BeforeDeleteContext")
+}
+
+func (c *contextDocument) AfterDeleteContext(ctx context.Context, q *reform.Querier) error {
+	return c.call(ctx, q, "AfterDeleteContext")
+}
+
+func (c *contextDocument) AfterFindContext(ctx context.Context, q *reform.Querier) error {
+	return c.call(ctx, q, "AfterFindContext")
+}
+
+// check interfaces
+var (
+	_ reform.BeforeInserterContext = (*contextDocument)(nil)
+	_ reform.AfterInserterContext  = (*contextDocument)(nil)
+	_ reform.BeforeUpdaterContext  = (*contextDocument)(nil)
+	_ reform.AfterUpdaterContext   = (*contextDocument)(nil)
+	_ reform.AfterSaverContext     = (*contextDocument)(nil)
+	_ reform.BeforeDeleterContext  = (*contextDocument)(nil)
+	_ reform.AfterDeleterContext   = (*contextDocument)(nil)
+	_ reform.AfterFinderContext    = (*contextDocument)(nil)
+)
+
+func TestContextHooks(t *testing.T) {
+	db, tx := setupTX(t)
+	defer teardown(t, db)
+	defer func() {
+		require.NoError(t, tx.Rollback())
+	}()
+
+	q := tx.WithContext(context.WithValue(context.Background(), ctxKey("k"), "hooks"))
+	c := &contextDocument{
+		hookedDocument: &hookedDocument{Record: &Document{Name: "hooks"}},
+		t:              t,
+	}
+
+	// documents table is empty, so counts show that hooks' queries are made in the same transaction
+	require.NoError(t, q.Save(c))
+	require.NoError(t, q.Update(c))
+	require.NoError(t, q.FindByPrimaryKeyTo(c, c.PKValue()))
+	require.NoError(t, q.Delete(c))
+	assert.Equal(t, []string{
+		"BeforeInsertContext documents=0",
+		"AfterInsertContext documents=1",
+		"AfterSaveContext documents=1",
+		"BeforeUpdateContext documents=1",
+		"AfterUpdateContext documents=1",
+		"AfterFindContext documents=1",
+		"BeforeDeleteContext documents=1",
+		"AfterDeleteContext documents=0", // soft-deleted
+	}, c.hookedDocument.calls)
+}
+
+func TestUpdateChanged(t *testing.T) {
+	db, tx := setupTX(t)
+	defer teardown(t, db)
+	defer func() {
+		require.NoError(t, tx.Rollback())
+	}()
+
+	doc := &Document{Name: "changed"}
+	require.NoError(t, tx.Insert(doc))
+	assert.Empty(t, doc.Changed())
+
+	t.Run("Changed", func(t *testing.T) {
+		var found Document
+		require.NoError(t, tx.FindByPrimaryKeyTo(&found, doc.ID))
+		assert.Empty(t, found.Changed())
+
+		found.Name = "other"
+		assert.Equal(t, []string{"name"}, found.Changed())
+		found.Name = "changed"
+		assert.Empty(t, found.Changed())
+
+		// pointer field changed in place
+		now := time.Now()
+		found.DeletedAt = &now
+		require.Equal(t, []string{"deleted_at"}, found.Changed())
+		*found.DeletedAt = now.Add(time.Hour)
+		assert.Equal(t, []string{"deleted_at"}, found.Changed())
+		found.DeletedAt = nil
+		assert.Empty(t, found.Changed())
+
+		// struct without snapshot
+		assert.Equal(t, DocumentTable.Columns(), new(Document).Changed())
+	})
+
+	t.Run("UpdateChanged", func(t *testing.T) {
+		var found Document
+		require.NoError(t, tx.FindByPrimaryKeyTo(&found, doc.ID))
+		version := found.LockVersion
+
+		assert.Equal(t, reform.ErrNothingToUpdate, tx.UpdateChanged(&found))
+		assert.Equal(t, version, found.LockVersion)
+
+		found.Name = "updated"
+		require.NoError(t, tx.UpdateChanged(&found))
+		assert.Equal(t, version+1, found.LockVersion)
+		assert.NotNil(t, found.UpdatedAt)
+		assert.Empty(t, found.Changed())
+		assert.Equal(t, reform.ErrNothingToUpdate, tx.UpdateChanged(&found))
+
+		var reloaded Document
+		require.NoError(t, tx.FindByPrimaryKeyTo(&reloaded, doc.ID))
+		assert.Equal(t, "updated", reloaded.Name)
+		assert.Equal(t, found.LockVersion, reloaded.LockVersion)
+
+		// stale record is detected even if only changed columns are updated
+		reloaded.Name = "stale"
+		found.Name = "fresh"
+		require.NoError(t, tx.UpdateChanged(&found))
+		assert.Equal(t, reform.ErrStaleRecord, tx.UpdateChanged(&reloaded))
+	})
+
+	t.Run("UpdateColumns", func(t *testing.T) {
+		var found Document
+		require.NoError(t, tx.FindByPrimaryKeyTo(&found, doc.ID))
+
+		found.Name = "columns"
+		now := time.Now()
+		found.DeletedAt = &now
+		require.NoError(t, tx.UpdateColumns(&found, "name"))
+		assert.Equal(t, []string{"deleted_at"}, found.Changed())
+	})
+
+	t.Run("Upsert", func(t *testing.T) {
+		var found Document
+		require.NoError(t, tx.FindByPrimaryKeyTo(&found, doc.ID))
+
+		withIdentityInsert(t, tx.Querier, "documents", func() {
+			found.Name = "upsert"
+			require.Equal(t, []string{"name"}, found.Changed())
+			require.NoError(t, tx.Upsert(&found, []string{"id"}, "name"))
+			assert.Empty(t, found.Changed())
+
+			found.Name = "upsert multi"
+			require.NoError(t, tx.UpsertMulti([]reform.Struct{&found}, []string{"id"}, "name"))
+			assert.Empty(t, found.Changed())
+		})
+	})
+
+	t.Run("NoSnapshot", func(t *testing.T) {
+		var found Document
+		require.NoError(t, tx.FindByPrimaryKeyTo(&found, doc.ID))
+
+		updated := &Document{
+			ID:          found.ID,
+			Name:        "no snapshot",
+			LockVersion: found.LockVersion,
+			CreatedAt:   found.CreatedAt,
+		}
+		require.NoError(t, tx.UpdateChanged(updated))
+		assert.Empty(t, updated.Changed())
+
+		require.NoError(t, tx.FindByPrimaryKeyTo(&found, doc.ID))
+		assert.Equal(t, "no snapshot", found.Name)
+	})
+
+	t.Run("NoPK", func(t *testing.T) {
+		assert.Equal(t, reform.ErrNoPK, tx.UpdateChanged(new(Document)))
+	})
+}
@@ -109,6 +109,35 @@ func ExampleQuerier_SelectRows() {
 	// ID: 2 (int32), GroupID: 65534 (*int32), Name: `Garrick Muller` (string), Email: `muller_garrick@example.com` (*string), CreatedAt: 2009-12-12 12:34:56 +0000 UTC (time.Time), UpdatedAt: <nil> (*time.Time)
 }
 
+func ExampleQuerier_SelectEach() {
+	tail := fmt.Sprintf("WHERE created_at < %s ORDER BY id", DB.Placeholder(1))
+	y2010 := time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC)
+	err := DB.SelectEach(PersonTable, func(str reform.Struct) error {
+		fmt.Println(str.(*Person).Name)
+		return nil
+	}, tail, y2010)
+	if err != nil {
+		log.Fatal(err)
+	}
+	// Output:
+	// Denis Mills
+	// Garrick Muller
+}
+
+func ExampleQuerier_Iter() {
+	tail := fmt.Sprintf("WHERE created_at < %s ORDER BY id", DB.Placeholder(1))
+	y2010 := time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC)
+	for str, err := range DB.Iter(PersonTable, tail, y2010) {
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(str.(*Person).Name)
+	}
+	// Output:
+	// Denis Mills
+	// Garrick Muller
+}
+
 func ExampleQuerier_SelectOneTo() {
 	var person Person
 	tail := fmt.Sprintf("WHERE created_at < %s ORDER BY id", DB.Placeholder(1))
@@ -161,12 +190,16 @@ func ExampleQuerier_InsertMulti() {
 		fmt.Printf("Inserted %d persons\n", len(batch))
 	}
 
-	// note that ID is not filled
-	fmt.Println(persons[0].(*Person).ID, persons[0].(*Person).Name)
+	// note that ID is filled for most dialects, see InsertMulti documentation
+	person, err := DB.FindByPrimaryKeyFrom(PersonTable, persons[0].(*Person).ID)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(person.(*Person).Name)
 	// Output:
 	// Inserted 3 persons
 	// Inserted 2 persons
-	// 0 Alexey Palazhchenko
+	// Alexey Palazhchenko
 }
 
 func ExampleQuerier_Query() {
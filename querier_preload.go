@@ -0,0 +1,167 @@
+package reform
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Relation describes a relation between parent structs and related structs for Querier.Preload.
+// Use BelongsTo and HasMany to create it.
+type Relation struct {
+	view    View
+	column  string
+	hasMany bool
+}
+
+// BelongsTo returns a relation of parent structs with given foreign key column to structs of referenced view.
+// For example, BelongsTo("person_id", PersonTable) for PersonProject structs with "person_id,fk=people.id" tag.
+func BelongsTo(column string, view View) Relation {
+	return Relation{
+		view:   view,
+		column: column,
+	}
+}
+
+// HasMany returns a relation of parent structs to structs of given view with given foreign key column
+// referencing parents. For example, HasMany(PersonProjectView, "person_id") for Person structs.
+func HasMany(view View, column string) Relation {
+	return Relation{
+		view:    view,
+		column:  column,
+		hasMany: true,
+	}
+}
+
+// qualifiedName returns view name qualified with schema if it is not empty.
+func qualifiedName(view View) string {
+	if s := view.Schema(); s != "" {
+		return s + "." + view.Name()
+	}
+	return view.Name()
+}
+
+// foreignKey returns foreign key with given column of given view, checking that it references given table.
+func foreignKey(view View, column string, table View) (*ForeignKey, error) {
+	if fkView, ok := view.(ForeignKeysView); ok {
+		for _, fk := range fkView.ForeignKeys() {
+			if fk.Column != column {
+				continue
+			}
+			if fk.RefTable != qualifiedName(table) {
+				return nil, fmt.Errorf("reform: foreign key %s of %s references %s, not %s",
+					column, qualifiedName(view), fk.RefTable, qualifiedName(table))
+			}
+			return &fk, nil
+		}
+	}
+	return nil, fmt.Errorf("reform: %s has no foreign key %s", qualifiedName(view), column)
+}
+
+// columnIndex returns an index of given column in view's columns.
+func columnIndex(view View, column string) (int, error) {
+	for i, c := range view.Columns() {
+		if c == column {
+			return i, nil
+		}
+	}
+	return -1, &UnexpectedColumnsError{Columns: []string{column}}
+}
+
+// relationKey returns value of relation column used as a map key: pointers are dereferenced,
+// nil is returned for nil pointers.
+func relationKey(value interface{}) (interface{}, error) {
+	v := reflect.ValueOf(value)
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+	if !v.Type().Comparable() {
+		return nil, fmt.Errorf("reform: relation column type %s is not comparable", v.Type())
+	}
+	return v.Interface(), nil
+}
+
+// Preload loads structs related to parent structs with a single query (or several queries if the number of
+// parents exceeds dialect's placeholders limit, see FindAllFrom), and returns them in a map.
+// Map keys are values of referenced column (with pointers dereferenced): for BelongsTo relation,
+// they are values of parents' foreign key column, and each slice contains one struct;
+// for HasMany relation, they are values of parents' referenced column.
+// Parents with NULL keys and parents without related structs are not present in the map.
+//
+// All parents should belong to the same view/table. Foreign key column and referenced column should have
+// the same Go types (ignoring pointers), and those types should be comparable.
+func (q *Querier) Preload(parents []Struct, relation Relation) (map[interface{}][]Struct, error) {
+	if len(parents) == 0 {
+		return nil, nil
+	}
+
+	view := parents[0].View()
+	for _, str := range parents {
+		if str.View() != view {
+			return nil, &DifferentTablesError{Method: "Preload", First: view.Name(), Second: str.View().Name()}
+		}
+	}
+
+	// columns of parents and related structs
+	var parentColumn, column string
+	if relation.hasMany {
+		fk, err := foreignKey(relation.view, relation.column, view)
+		if err != nil {
+			return nil, err
+		}
+		parentColumn, column = fk.RefColumn, fk.Column
+	} else {
+		fk, err := foreignKey(view, relation.column, relation.view)
+		if err != nil {
+			return nil, err
+		}
+		parentColumn, column = fk.Column, fk.RefColumn
+	}
+
+	parentIndex, err := columnIndex(view, parentColumn)
+	if err != nil {
+		return nil, err
+	}
+	index, err := columnIndex(relation.view, column)
+	if err != nil {
+		return nil, err
+	}
+
+	// collect unique keys in parents order
+	keys := make([]interface{}, 0, len(parents))
+	seen := make(map[interface{}]struct{}, len(parents))
+	for _, str := range parents {
+		key, err := relationKey(str.Values()[parentIndex])
+		if err != nil {
+			return nil, err
+		}
+		if key == nil {
+			continue
+		}
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		keys = append(keys, key)
+	}
+
+	structs, err := q.FindAllFrom(relation.view, column, keys...)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[interface{}][]Struct, len(keys))
+	for _, str := range structs {
+		key, err := relationKey(str.Values()[index])
+		if err != nil {
+			return nil, err
+		}
+		if key == nil {
+			continue
+		}
+		res[key] = append(res[key], str)
+	}
+	return res, nil
+}
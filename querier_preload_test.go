@@ -0,0 +1,74 @@
+package reform_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mc2soft/reform"
+	. "github.com/mc2soft/reform/internal/test/models"
+)
+
+func TestPreload(t *testing.T) {
+	db := setupDB(t)
+	defer teardown(t, db)
+
+	t.Run("BelongsTo", func(t *testing.T) {
+		parents, err := db.FindAllFrom(PersonProjectView, "person_id", 102, 103)
+		require.NoError(t, err)
+		require.Len(t, parents, 5)
+
+		projects, err := db.Preload(parents, reform.BelongsTo("project_id", ProjectTable))
+		require.NoError(t, err)
+		require.Len(t, projects, 3)
+		for _, id := range []string{"baron", "queen", "traveler"} {
+			require.Len(t, projects[id], 1, "%s", id)
+			assert.Equal(t, id, projects[id][0].(*Project).ID)
+		}
+
+		persons, err := db.Preload(parents, reform.BelongsTo("person_id", PersonTable))
+		require.NoError(t, err)
+		require.Len(t, persons, 2)
+		assert.Equal(t, "Elfrieda Abbott", persons[int32(102)][0].(*Person).Name)
+	})
+
+	t.Run("HasMany", func(t *testing.T) {
+		parents, err := db.FindAllFrom(PersonTable, "id", 101, 102, 103, 1)
+		require.NoError(t, err)
+		require.Len(t, parents, 4)
+
+		pps, err := db.Preload(parents, reform.HasMany(PersonProjectView, "person_id"))
+		require.NoError(t, err)
+		assert.Len(t, pps, 3)
+		assert.Len(t, pps[int32(101)], 1)
+		assert.Len(t, pps[int32(102)], 2)
+		assert.Len(t, pps[int32(103)], 3)
+		assert.NotContains(t, pps, int32(1))
+		for _, pp := range pps[int32(103)] {
+			assert.Equal(t, int32(103), pp.(*PersonProject).PersonID)
+		}
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		res, err := db.Preload(nil, reform.HasMany(PersonProjectView, "person_id"))
+		assert.NoError(t, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		parents, err := db.FindAllFrom(PersonTable, "id", 102)
+		require.NoError(t, err)
+
+		_, err = db.Preload(parents, reform.BelongsTo("group_id", ProjectTable))
+		assert.EqualError(t, err, "reform: people has no foreign key group_id")
+
+		_, err = db.Preload(parents, reform.HasMany(PersonProjectView, "project_id"))
+		assert.EqualError(t, err, "reform: foreign key project_id of person_project references projects, not people")
+
+		project, err := db.FindByPrimaryKeyFrom(ProjectTable, "baron")
+		require.NoError(t, err)
+		_, err = db.Preload(append(parents, project), reform.HasMany(PersonProjectView, "person_id"))
+		assert.EqualError(t, err, "reform: different tables in Preload: people and projects")
+	})
+}
@@ -2,7 +2,11 @@ package reform
 
 import (
 	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"iter"
 	"strings"
 )
 
@@ -28,42 +32,171 @@ func (q *Querier) NextRow(str Struct, rows *sql.Rows) error {
 		return err
 	}
 
-	if af, ok := str.(AfterFinder); ok {
+	return q.afterFind(str)
+}
+
+// afterFind calls AfterFindContext() or AfterFind() if str implements AfterFinderContext or AfterFinder,
+// and then takes str's Snapshot.
+func (q *Querier) afterFind(str Struct) error {
+	var err error
+	if af, ok := str.(AfterFinderContext); ok {
+		err = af.AfterFindContext(q.ctx, q)
+	} else if af, ok := str.(AfterFinder); ok {
 		err = af.AfterFind()
 	}
-	return err
+	if err != nil {
+		return err
+	}
+
+	takeSnapshot(str)
+	return nil
+}
+
+// softDeleteTail returns a given tail of SELECT query for given view. For SoftDeleteTable,
+// unless Querier is WithDeleted, condition excluding soft-deleted rows is added to it (see addCond).
+func (q *Querier) softDeleteTail(view View, tail string) string {
+	table, ok := view.(SoftDeleteTable)
+	if !ok || q.withDeleted {
+		return tail
+	}
+
+	column := q.QualifiedView(table) + "." + q.QuoteIdentifier(table.Columns()[table.SoftDeleteColumnIndex()])
+	return addCond(tail, column+" IS NULL")
+}
+
+// tailClauses contains keywords of SELECT query clauses which may follow WHERE clause.
+//
+//nolint:gochecknoglobals
+var tailClauses = map[string]struct{}{
+	"group":     {},
+	"having":    {},
+	"window":    {},
+	"order":     {},
+	"limit":     {},
+	"offset":    {},
+	"fetch":     {},
+	"for":       {},
+	"lock":      {},
+	"union":     {},
+	"intersect": {},
+	"except":    {},
+	"option":    {},
+}
+
+// addCond returns SELECT or UPDATE query tail with given condition added to its top-level WHERE clause by AND,
+// or with a new WHERE clause before the first clause which follows it (or at the end) if there is none.
+// String literals, quoted identifiers, comments and parenthesized expressions are skipped.
+func addCond(tail, cond string) string {
+	where, end := -1, len(tail)
+	var depth int
+	s := tail
+loop:
+	for len(s) > 0 {
+		c := s[0]
+		switch {
+		case c == '-' && strings.HasPrefix(s, "--"):
+			if !strings.Contains(s, "\n") {
+				tail += "\n" // terminate the last line comment
+				end = len(tail)
+				break loop
+			}
+			s = skipUntil(s[2:], "\n")
+
+		case c == '/' && strings.HasPrefix(s, "/*"):
+			s = skipUntil(s[2:], "*/")
+
+		case c == '\'' || c == '"' || c == '`':
+			s = skipQuoted(s[1:], c)
+
+		case c == '[':
+			s = skipUntil(s[1:], "]")
+
+		case c == '(':
+			depth++
+			s = s[1:]
+
+		case c == ')':
+			depth--
+			s = s[1:]
+
+		case isWordByte(c):
+			n := 1
+			for n < len(s) && (isWordByte(s[n]) || s[n] == '.') {
+				n++
+			}
+			if depth == 0 {
+				pos := len(tail) - len(s)
+				word := strings.ToLower(s[:n])
+				if _, ok := tailClauses[word]; ok {
+					end = pos
+					break loop
+				}
+				if word == "where" && where < 0 {
+					where = pos + n
+				}
+			}
+			s = s[n:]
+
+		default:
+			s = s[1:]
+		}
+	}
+
+	// keep newlines terminating line comments
+	rest := strings.TrimLeft(tail[end:], " \t\r\n")
+	if where < 0 {
+		return strings.TrimSpace(strings.TrimRight(tail[:end], " \t") + " WHERE " + cond + " " + rest)
+	}
+	expr := strings.TrimRight(strings.TrimLeft(tail[where:end], " \t\r\n"), " \t")
+	return strings.TrimSpace(tail[:where] + " " + cond + " AND (" + expr + ") " + rest)
 }
 
 // selectQuery returns full SELECT query for given view and tail.
 func (q *Querier) selectQuery(view View, tail string, limit1 bool) string {
 	query := q.startQuery("SELECT")
 
-	if limit1 && q.SelectLimitMethod() == SelectTop {
+	// TOP can't be combined with OFFSET ... FETCH; such tail limits rows itself
+	if limit1 && q.SelectLimitMethod() == SelectTop && !hasOffset(tail) {
 		query += " TOP 1"
 	}
 
-	return fmt.Sprintf("%s %s FROM %s %s",
-		query, strings.Join(q.QualifiedColumns(view), ", "), q.QualifiedView(view), tail)
+	from := q.QualifiedView(view)
+	columns := view.Columns()
+	for i, c := range columns {
+		columns[i] = from + "." + q.QuoteIdentifier(c)
+	}
+
+	return fmt.Sprintf("%s %s FROM %s %s", query, strings.Join(columns, ", "), from, q.softDeleteTail(view, tail))
+}
+
+// hasOffset returns true if query tail contains OFFSET clause.
+func hasOffset(tail string) bool {
+	for _, token := range sqlTokens(tail) {
+		if token == "offset" {
+			return true
+		}
+	}
+	return false
 }
 
 // SelectOneTo queries str's View with tail and args and scans first result to str.
+// Args may consist of a single TailExpression (see package where).
 // If str implements AfterFinder, it also calls AfterFind().
 //
 // If there are no rows in result, it returns ErrNoRows. It also may return QueryRow(), Scan()
 // and AfterFinder errors.
 func (q *Querier) SelectOneTo(str Struct, tail string, args ...interface{}) error {
+	tail, args = q.expandTail(tail, args, 1)
 	query := q.selectQuery(str.View(), tail, true)
-	if err := q.QueryRow(query, args...).Scan(str.Pointers()...); err != nil {
+	if err := q.scanRow(str.Pointers(), query, args...); err != nil {
 		return err
 	}
 
-	if af, ok := str.(AfterFinder); ok {
-		return af.AfterFind()
-	}
-	return nil
+	return q.afterFind(str)
 }
 
 // SelectOneFrom queries view with tail and args and scans first result to new Struct str.
+// Args may consist of a single TailExpression (see package where).
 // If str implements AfterFinder, it also calls AfterFind().
 //
 // If there are no rows in result, it returns nil, ErrNoRows. It also may return QueryRow(), Scan()
@@ -77,22 +210,39 @@ func (q *Querier) SelectOneFrom(view View, tail string, args ...interface{}) (St
 }
 
 // SelectRows queries view with tail and args and returns rows. They can then be iterated with NextRow().
+// Args may consist of a single TailExpression (see package where).
 // It is caller's responsibility to call rows.Close().
 //
 // In case of error rows will be nil. Error is never ErrNoRows.
 //
 // See example for idiomatic usage.
 func (q *Querier) SelectRows(view View, tail string, args ...interface{}) (*sql.Rows, error) {
+	tail, args = q.expandTail(tail, args, 1)
 	query := q.selectQuery(view, tail, false)
 	return q.Query(query, args...)
 }
 
 // SelectAllFrom queries view with tail and args and returns a slice of new Structs.
+// Args may consist of a single TailExpression (see package where).
 // If view's Struct implements AfterFinder, it also calls AfterFind().
 //
 // In case of query error slice will be nil. If error is encountered during iteration,
 // partial result and error will be returned. Error is never ErrNoRows.
 func (q *Querier) SelectAllFrom(view View, tail string, args ...interface{}) (structs []Struct, err error) {
+	err = q.SelectEach(view, func(str Struct) error {
+		structs = append(structs, str)
+		return nil
+	}, tail, args...)
+	return
+}
+
+// SelectEach queries view with tail and args and calls f for each result row scanned to new Struct.
+// Args may consist of a single TailExpression (see package where).
+// If view's Struct implements AfterFinder, it also calls AfterFind() before f.
+// Rows are always closed before return.
+//
+// It stops on the first query, iteration or f error and returns it. Error is never ErrNoRows.
+func (q *Querier) SelectEach(view View, f func(str Struct) error, tail string, args ...interface{}) (err error) {
 	var rows *sql.Rows
 	rows, err = q.SelectRows(view, tail, args...)
 	if err != nil {
@@ -111,7 +261,9 @@ func (q *Querier) SelectAllFrom(view View, tail string, args ...interface{}) (st
 			break
 		}
 
-		structs = append(structs, str)
+		if err = f(str); err != nil {
+			return
+		}
 	}
 	if err == ErrNoRows {
 		err = nil
@@ -119,6 +271,37 @@ func (q *Querier) SelectAllFrom(view View, tail string, args ...interface{}) (st
 	return
 }
 
+// errStopIteration is used internally by Iter to stop SelectEach when loop body breaks.
+var errStopIteration = errors.New("reform: stop iteration") //nolint:gochecknoglobals
+
+// Iter returns an iterator over result rows of view query with tail and args, scanned to new Structs.
+// Args may consist of a single TailExpression (see package where).
+// If view's Struct implements AfterFinder, it also calls AfterFind().
+//
+// Query is executed each time iteration starts. Rows are always closed when iteration ends,
+// including when loop body breaks. Query or iteration error is yielded once with nil Struct as the last pair.
+// Error is never ErrNoRows.
+//
+//	for str, err := range q.Iter(PersonTable, "ORDER BY id") {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(str.(*Person).Name)
+//	}
+func (q *Querier) Iter(view View, tail string, args ...interface{}) iter.Seq2[Struct, error] {
+	return func(yield func(Struct, error) bool) {
+		err := q.SelectEach(view, func(str Struct) error {
+			if !yield(str, nil) {
+				return errStopIteration
+			}
+			return nil
+		}, tail, args...)
+		if err != nil && err != errStopIteration {
+			yield(nil, err)
+		}
+	}
+}
+
 // findTail returns a tail of SELECT query for given view, column and arg.
 func (q *Querier) findTail(view string, column string, arg interface{}, limit1 bool) (tail string, needArg bool) {
 	qi := q.QuoteIdentifier(view) + "." + q.QuoteIdentifier(column)
@@ -179,33 +362,443 @@ func (q *Querier) FindRows(view View, column string, arg interface{}) (*sql.Rows
 // FindAllFrom queries view with column and args and returns a slice of new Structs.
 // If view's Struct implements AfterFinder, it also calls AfterFind().
 //
+// If the number of args exceeds dialect's placeholders limit, several queries are used, and their results
+// are concatenated. They are executed in the transaction if Querier is a part of it, and independently otherwise.
+// If there are no args, it returns nil slice without querying.
+//
 // In case of query error slice will be nil. If error is encountered during iteration,
 // partial result and error will be returned. Error is never ErrNoRows.
 func (q *Querier) FindAllFrom(view View, column string, args ...interface{}) ([]Struct, error) {
-	p := strings.Join(q.Placeholders(1, len(args)), ", ")
-	qi := q.QualifiedView(view) + "." + q.QuoteIdentifier(column)
-	tail := fmt.Sprintf("WHERE %s IN (%s)", qi, p)
-	return q.SelectAllFrom(view, tail, args...)
+	size := len(args)
+	if limit := q.MaxPlaceholders(); limit > 0 && size > limit {
+		size = limit
+	}
+
+	var res []Struct
+	for start := 0; start < len(args); start += size {
+		end := start + size
+		if end > len(args) {
+			end = len(args)
+		}
+
+		p := strings.Join(q.Placeholders(1, end-start), ", ")
+		qi := q.QualifiedView(view) + "." + q.QuoteIdentifier(column)
+		tail := fmt.Sprintf("WHERE %s IN (%s)", qi, p)
+		structs, err := q.SelectAllFrom(view, tail, args[start:end]...)
+		res = append(res, structs...)
+		if err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+// NextRowMulti scans next result row from rows to several structs, typically for a query made by SelectJoinedRows.
+// Columns of the row should contain all columns of each struct's view in order.
+// It returns a slice of the same length as strs, with nil for structs with all columns NULL
+// (typically, a missing side of outer JOIN); their fields are not changed. For other structs implementing
+// AfterFinder, it also calls AfterFind().
+// It is caller's responsibility to call rows.Close().
+//
+// If there is no next result row, it returns ErrNoRows. It also may return rows.Err(), rows.Scan()
+// and AfterFinder errors.
+func (q *Querier) NextRowMulti(rows *sql.Rows, strs ...Struct) ([]Struct, error) {
+	if !rows.Next() {
+		err := rows.Err()
+		if err == nil {
+			err = ErrNoRows
+		}
+		return nil, err
+	}
+
+	// scan raw values first to find structs with all columns NULL
+	pointers := make([][]interface{}, len(strs))
+	var n int
+	for i, str := range strs {
+		pointers[i] = str.Pointers()
+		n += len(pointers[i])
+	}
+	raw := make([]interface{}, n)
+	for i := range raw {
+		raw[i] = new(interface{})
+	}
+	if err := rows.Scan(raw...); err != nil {
+		return nil, err
+	}
+
+	res := make([]Struct, len(strs))
+	dest := make([]interface{}, 0, n)
+	var start int
+	for i, str := range strs {
+		end := start + len(pointers[i])
+		null := true
+		for _, r := range raw[start:end] {
+			if *(r.(*interface{})) != nil {
+				null = false
+				break
+			}
+		}
+		if null {
+			dest = append(dest, raw[start:end]...)
+		} else {
+			dest = append(dest, pointers[i]...)
+			res[i] = str
+		}
+		start = end
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return nil, err
+	}
+
+	for _, str := range res {
+		if str == nil {
+			continue
+		}
+		if err := q.afterFind(str); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// SelectJoinedRows queries qualified columns of all views with tail and args, and returns rows.
+// They can then be iterated with NextRowMulti().
+// Tail should start with FROM clause and contain JOIN clauses for all views, which should be referenced by their
+// qualified names (see QualifiedView). Soft-deleted rows of SoftDeleteTable are not excluded.
+// Args may consist of a single TailExpression (see package where), which is appended to tail;
+// its column names are not qualified, so they should be unambiguous.
+// It is caller's responsibility to call rows.Close().
+//
+// In case of error rows will be nil. Error is never ErrNoRows.
+func (q *Querier) SelectJoinedRows(views []View, tail string, args ...interface{}) (*sql.Rows, error) {
+	tail, args = q.expandTail(tail, args, 1)
+
+	var columns []string
+	for _, view := range views {
+		columns = append(columns, q.QualifiedColumns(view)...)
+	}
+	query := fmt.Sprintf("%s %s %s", q.startQuery("SELECT"), strings.Join(columns, ", "), tail)
+	return q.Query(query, args...)
+}
+
+// SelectJoined queries qualified columns of all views with tail and args (see SelectJoinedRows),
+// and returns a slice of rows, each with new Structs for all views, or nil for missing ones (see NextRowMulti).
+// If view's Struct implements AfterFinder, it also calls AfterFind().
+//
+// In case of query error slice will be nil. If error is encountered during iteration,
+// partial result and error will be returned. Error is never ErrNoRows.
+func (q *Querier) SelectJoined(views []View, tail string, args ...interface{}) (res [][]Struct, err error) {
+	var rows *sql.Rows
+	rows, err = q.SelectJoinedRows(views, tail, args...)
+	if err != nil {
+		return
+	}
+	defer func() {
+		e := rows.Close()
+		if err == nil {
+			err = e
+		}
+	}()
+
+	for {
+		strs := make([]Struct, len(views))
+		for i, view := range views {
+			strs[i] = view.NewStruct()
+		}
+		if strs, err = q.NextRowMulti(rows, strs...); err != nil {
+			break
+		}
+
+		res = append(res, strs)
+	}
+	if err == ErrNoRows {
+		err = nil
+	}
+	return
+}
+
+// PageOrder is a column of keyset pagination order for SelectPage.
+type PageOrder struct {
+	Column Column
+	Desc   bool
+}
+
+// PageRequest describes a page for SelectPage.
+type PageRequest struct {
+	// OrderBy contains columns to order by. They should not contain NULL values.
+	// For Table, missing primary key columns are added in ascending order, so the order is always unique.
+	// For other views, it is caller's responsibility to make the order unique.
+	OrderBy []PageOrder
+
+	// After is a cursor returned by the previous SelectPage call with the same OrderBy,
+	// or empty string for the first page.
+	After string
+
+	// Limit is a maximum number of structs on the page. It should be positive.
+	Limit int
+
+	// Where is an optional filter: SQL condition without WHERE keyword, with placeholders for Args
+	// starting from 1. It is combined with cursor predicate by AND, and should be the same for all pages.
+	// Alternatively, Where may be empty, and Args may consist of a single where.Cond
+	// (or other TailExpression rendering into WHERE clause only).
+	Where string
+	Args  []interface{}
+}
+
+// pageCursor is a SelectPage cursor with sort key values of the last row of the page.
+type pageCursor struct {
+	Order  []string          `json:"o"`
+	Values []json.RawMessage `json:"v"`
+}
+
+// pageOrder returns full order for given view and requested order, and indexes of its columns.
+func pageOrder(view View, orderBy []PageOrder) (order []PageOrder, indexes []int, err error) {
+	columns := view.Columns()
+	index := func(column Column) int {
+		for i, c := range columns {
+			if c == string(column) {
+				return i
+			}
+		}
+		return -1
+	}
+
+	order = append(order, orderBy...)
+	if table, ok := view.(Table); ok {
+		for _, pk := range table.PKColumnIndexes() {
+			var found bool
+			for _, o := range orderBy {
+				if string(o.Column) == columns[pk] {
+					found = true
+					break
+				}
+			}
+			if !found {
+				order = append(order, PageOrder{Column: Column(columns[pk])})
+			}
+		}
+	}
+	if len(order) == 0 {
+		err = fmt.Errorf("reform: no order columns")
+		return
+	}
+
+	indexes = make([]int, len(order))
+	for i, o := range order {
+		if indexes[i] = index(o.Column); indexes[i] < 0 {
+			err = &UnexpectedColumnsError{Columns: []string{string(o.Column)}}
+			return
+		}
+	}
+	return
+}
+
+// SelectPage queries view with keyset pagination and returns a page of new Structs,
+// and a cursor for the next page, or empty string if it is the last page.
+// If view's Struct implements AfterFinder, it also calls AfterFind().
+//
+// Structs are ordered by req.OrderBy, and only rows after req.After cursor are returned.
+// Cursor is an opaque string which contains JSON-encoded sort key values of the last struct of the page,
+// so fields of order columns should support JSON encoding.
+//
+// It returns ErrInvalidPageCursor if req.After is malformed or was returned for a different order.
+// In case of query error slice will be nil. Error is never ErrNoRows.
+func (q *Querier) SelectPage(view View, req PageRequest) (structs []Struct, next string, err error) {
+	if req.Limit <= 0 {
+		err = fmt.Errorf("reform: page limit should be positive, got %d", req.Limit)
+		return
+	}
+
+	order, indexes, err := pageOrder(view, req.OrderBy)
+	if err != nil {
+		return
+	}
+	columns := make([]string, len(order))
+	keys := make([]string, len(order)) // cursor is valid only for the same order
+	for i, o := range order {
+		columns[i] = string(o.Column)
+		keys[i] = columns[i]
+		if o.Desc {
+			keys[i] += " DESC"
+		}
+	}
+
+	var conds []string
+	var args []interface{}
+	if req.Where != "" || len(req.Args) > 0 {
+		var filter string
+		filter, args = q.expandTail(req.Where, req.Args, 1)
+		if req.Where == "" {
+			filter = strings.TrimPrefix(filter, "WHERE ")
+		}
+		conds = append(conds, "("+filter+")")
+	}
+
+	if req.After != "" {
+		var values []interface{}
+		if values, err = decodePageCursor(view, keys, indexes, req.After); err != nil {
+			return
+		}
+
+		// (c1 > v1) OR (c1 = v1 AND c2 > v2) OR ... with < for descending order
+		disjuncts := make([]string, len(order))
+		for i, o := range order {
+			conjuncts := make([]string, i+1)
+			for j := 0; j < i; j++ {
+				args = append(args, values[j])
+				conjuncts[j] = q.QuoteIdentifier(columns[j]) + " = " + q.Placeholder(len(args))
+			}
+			op := " > "
+			if o.Desc {
+				op = " < "
+			}
+			args = append(args, values[i])
+			conjuncts[i] = q.QuoteIdentifier(columns[i]) + op + q.Placeholder(len(args))
+			disjuncts[i] = "(" + strings.Join(conjuncts, " AND ") + ")"
+		}
+		conds = append(conds, "("+strings.Join(disjuncts, " OR ")+")")
+	}
+
+	var parts []string
+	if len(conds) > 0 {
+		parts = append(parts, "WHERE "+strings.Join(conds, " AND "))
+	}
+
+	orderBy := make([]string, len(order))
+	for i, o := range order {
+		orderBy[i] = q.QuoteIdentifier(columns[i])
+		if o.Desc {
+			orderBy[i] += " DESC"
+		}
+	}
+	parts = append(parts, "ORDER BY "+strings.Join(orderBy, ", "))
+
+	// select one more row to know if there is a next page
+	switch q.SelectLimitMethod() {
+	case Limit:
+		parts = append(parts, fmt.Sprintf("LIMIT %d", req.Limit+1))
+	case SelectTop:
+		parts = append(parts, fmt.Sprintf("OFFSET 0 ROWS FETCH NEXT %d ROWS ONLY", req.Limit+1))
+	default:
+		panic("reform: Unhandled SelectLimitMethod. Please report this bug.")
+	}
+
+	if structs, err = q.SelectAllFrom(view, strings.Join(parts, " "), args...); err != nil {
+		return
+	}
+	if len(structs) > req.Limit {
+		structs = structs[:req.Limit]
+		next, err = encodePageCursor(keys, indexes, structs[req.Limit-1])
+	}
+	return
+}
+
+// encodePageCursor returns SelectPage cursor for given order keys with sort key values of given struct.
+func encodePageCursor(keys []string, indexes []int, str Struct) (string, error) {
+	values := str.Values()
+	c := pageCursor{
+		Order:  keys,
+		Values: make([]json.RawMessage, len(indexes)),
+	}
+	for i, index := range indexes {
+		b, err := json.Marshal(values[index])
+		if err != nil {
+			return "", err
+		}
+		c.Values[i] = b
+	}
+
+	b, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// decodePageCursor returns sort key values from SelectPage cursor, checking that it matches given order keys.
+// Values are decoded into fields of a new view's struct, so they have the same types.
+func decodePageCursor(view View, keys []string, indexes []int, cursor string) ([]interface{}, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidPageCursor
+	}
+	var c pageCursor
+	if err = json.Unmarshal(b, &c); err != nil {
+		return nil, ErrInvalidPageCursor
+	}
+	if len(c.Order) != len(keys) || len(c.Values) != len(keys) {
+		return nil, ErrInvalidPageCursor
+	}
+	for i, key := range keys {
+		if c.Order[i] != key {
+			return nil, ErrInvalidPageCursor
+		}
+	}
+
+	str := view.NewStruct()
+	pointers := str.Pointers()
+	for i, index := range indexes {
+		if err = json.Unmarshal(c.Values[i], pointers[index]); err != nil {
+			return nil, ErrInvalidPageCursor
+		}
+	}
+
+	allValues := str.Values()
+	values := make([]interface{}, len(indexes))
+	for i, index := range indexes {
+		values[i] = allValues[index]
+	}
+	return values, nil
+}
+
+// compositePKTail returns a tail of SELECT query for given table with composite primary key and pk values.
+func (q *Querier) compositePKTail(table Table, pk interface{}) (tail string, args []interface{}, err error) {
+	pks := table.PKColumnIndexes()
+	args, _ = pk.([]interface{})
+	if len(args) != len(pks) {
+		err = fmt.Errorf("reform: %s has composite primary key of %d columns, got %#v", table.Name(), len(pks), pk)
+		return
+	}
+
+	allColumns := table.Columns()
+	columns := make([]string, len(pks))
+	for i, pk := range pks {
+		columns[i] = allColumns[pk]
+	}
+	tail = q.pkTail(table.Name(), columns, 1)
+	return
 }
 
 // FindByPrimaryKeyTo queries record's Table with primary key and scans first result to record.
+// For composite primary key, pk should be []interface{} with values of all primary key columns
+// in the order of Table.PKColumnIndexes().
 // If record implements AfterFinder, it also calls AfterFind().
 //
 // If there are no rows in result, it returns ErrNoRows. It also may return QueryRow(), Scan()
 // and AfterFinder errors.
 func (q *Querier) FindByPrimaryKeyTo(record Record, pk interface{}) error {
 	table := record.Table()
-	return q.FindOneTo(record, table.Columns()[table.PKColumnIndex()], pk)
+	if len(table.PKColumnIndexes()) == 1 {
+		return q.FindOneTo(record, table.Columns()[table.PKColumnIndex()], pk)
+	}
+
+	tail, args, err := q.compositePKTail(table, pk)
+	if err != nil {
+		return err
+	}
+	return q.SelectOneTo(record, tail, args...)
 }
 
 // FindByPrimaryKeyFrom queries table with primary key and scans first result to new Record.
+// For composite primary key, pk should be []interface{} with values of all primary key columns
+// in the order of Table.PKColumnIndexes().
 // If record implements AfterFinder, it also calls AfterFind().
 //
 // If there are no rows in result, it returns nil, ErrNoRows. It also may return QueryRow(), Scan()
 // and AfterFinder errors.
 func (q *Querier) FindByPrimaryKeyFrom(table Table, pk interface{}) (Record, error) {
 	record := table.NewRecord()
-	if err := q.FindOneTo(record, table.Columns()[table.PKColumnIndex()], pk); err != nil {
+	if err := q.FindByPrimaryKeyTo(record, pk); err != nil {
 		return nil, err
 	}
 	return record, nil
@@ -217,10 +810,12 @@ func (q *Querier) Reload(record Record) error {
 }
 
 // Count queries view with tail and args and returns a number (COUNT(*)) of matching rows.
+// Args may consist of a single TailExpression (see package where).
 func (q *Querier) Count(view View, tail string, args ...interface{}) (int, error) {
-	query := fmt.Sprintf("%s COUNT(*) FROM %s %s", q.startQuery("SELECT"), q.QualifiedView(view), tail)
+	tail, args = q.expandTail(tail, args, 1)
+	query := fmt.Sprintf("%s COUNT(*) FROM %s %s", q.startQuery("SELECT"), q.QualifiedView(view), q.softDeleteTail(view, tail))
 	var count int
-	if err := q.QueryRow(query, args...).Scan(&count); err != nil {
+	if err := q.scanRow([]interface{}{&count}, query, args...); err != nil {
 		return 0, err
 	}
 	return count, nil
@@ -1,13 +1,21 @@
 package reform_test
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
 	"time"
 
 	"github.com/AlekSi/pointer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/mc2soft/reform"
 	"github.com/mc2soft/reform/dialects/postgresql"
 	. "github.com/mc2soft/reform/internal/test/models"
+	"github.com/mc2soft/reform/where"
 )
 
 var (
@@ -219,12 +227,29 @@ func (s *ReformSuite) TestFindAllFrom() {
 	s.Nil(structs)
 	s.NoError(err)
 
+	structs, err = s.q.FindAllFrom(ProjectTable, "id")
+	s.Nil(structs)
+	s.NoError(err)
+
 	structs, err = s.q.FindAllFrom(ProjectTable, "invalid_column", nil)
 	s.Nil(structs)
 	s.Error(err)
 	s.NotEqual(reform.ErrNoRows, err)
 }
 
+func (s *ReformSuite) TestFindAllFromChunks() {
+	q := s.q.WithTag("test:%s", s.T().Name())
+	q.Dialect = placeholdersLimitDialect{Dialect: s.q.Dialect, limit: 2}
+
+	structs, err := q.FindAllFrom(PersonTable, "id", 1, 2, 101, 102, 103)
+	s.NoError(err)
+	ids := make([]int32, len(structs))
+	for i, str := range structs {
+		ids[i] = str.(*Person).ID
+	}
+	s.ElementsMatch([]int32{1, 2, 101, 102, 103}, ids)
+}
+
 func (s *ReformSuite) TestFindByPrimaryKeyTo() {
 	var person Person
 	err := s.q.FindByPrimaryKeyTo(&person, 1)
@@ -306,3 +331,336 @@ func (s *ReformSuite) TestSelectsSchema() {
 		&LegacyPerson{ID: 1003, Name: pointer.ToString("Dena Cummings")},
 	}, structs)
 }
+
+// topDialect is a Dialect with SelectTop limit method.
+type topDialect struct {
+	reform.Dialect
+}
+
+func (topDialect) SelectLimitMethod() reform.SelectLimitMethod {
+	return reform.SelectTop
+}
+
+func TestWhere(t *testing.T) {
+	db, tx := setupTX(t)
+	defer teardown(t, db)
+	defer func() {
+		require.NoError(t, tx.Rollback())
+	}()
+
+	t.Run("SelectAllFrom", func(t *testing.T) {
+		structs, err := tx.SelectAllFrom(PersonTable, "", where.Where(
+			where.Eq(PersonTable.C.Name, "Elfrieda Abbott"),
+			where.Or(where.IsNull(PersonTable.C.Email), where.Like(PersonTable.C.Email, "%@example.org")),
+		).OrderByDesc(PersonTable.C.ID).Limit(1).Offset(1))
+		require.NoError(t, err)
+		require.Len(t, structs, 1)
+		assert.Equal(t, int32(102), structs[0].(*Person).ID)
+	})
+
+	t.Run("SelectOneFrom", func(t *testing.T) {
+		str, err := tx.SelectOneFrom(PersonTable, "", where.Eq(PersonTable.C.ID, 102))
+		require.NoError(t, err)
+		assert.Equal(t, "Elfrieda Abbott", str.(*Person).Name)
+
+		_, err = tx.SelectOneFrom(PersonTable, "", where.In(PersonTable.C.ID))
+		assert.Equal(t, reform.ErrNoRows, err)
+	})
+
+	t.Run("Count", func(t *testing.T) {
+		count, err := tx.Count(PersonTable, "", where.In(PersonTable.C.ID, 101, 102, 103))
+		require.NoError(t, err)
+		assert.Equal(t, 3, count)
+	})
+
+	t.Run("UpdateView", func(t *testing.T) {
+		person := &Person{Name: "Updated", Email: pointer.ToString("updated@example.com")}
+		columns := []string{string(PersonTable.C.Name), string(PersonTable.C.Email)}
+		ra, err := tx.UpdateView(person, columns, "", where.In(PersonTable.C.ID, 102, 103))
+		require.NoError(t, err)
+		assert.Equal(t, uint(2), ra)
+
+		count, err := tx.Count(PersonTable, "", where.Eq(PersonTable.C.Name, "Updated"))
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+	})
+
+	t.Run("DeleteFrom", func(t *testing.T) {
+		doc1, doc2 := &Document{Name: "where1"}, &Document{Name: "where2"}
+		require.NoError(t, tx.Insert(doc1))
+		require.NoError(t, tx.Insert(doc2))
+
+		ra, err := tx.DeleteFrom(DocumentTable, "", where.Where(
+			where.Like(DocumentTable.C.Name, "where%"),
+			where.Ne(DocumentTable.C.ID, doc2.ID),
+		))
+		require.NoError(t, err)
+		assert.Equal(t, uint(1), ra)
+
+		structs, err := tx.SelectAllFrom(DocumentTable, "", where.Like(DocumentTable.C.Name, "where%"))
+		require.NoError(t, err)
+		require.Len(t, structs, 1)
+		assert.Equal(t, doc2.ID, structs[0].(*Document).ID)
+	})
+}
+
+func TestSelectTop(t *testing.T) {
+	db := setupDB(t)
+	defer teardown(t, db)
+
+	var queries []string
+	topDB := reform.NewDBFromInterface(db.DBInterface(), topDialect{db.Dialect}, db.Logger)
+	topDB.AddInterceptors(reform.InterceptorFunc(func(ctx context.Context, call reform.Call, next reform.CallHandler) reform.CallResult {
+		queries = append(queries, call.Query)
+		return next(ctx, call)
+	}))
+
+	// queries fail for dialects without TOP and OFFSET ... FETCH support
+	var person Person
+	_ = topDB.SelectOneTo(&person, "", where.Eq(PersonTable.C.ID, 102))
+	_ = topDB.SelectOneTo(&person, "", where.OrderBy(PersonTable.C.ID).Offset(1))
+	_ = topDB.SelectOneTo(&person, "", where.OrderBy(PersonTable.C.ID).Limit(1))
+	require.Len(t, queries, 3)
+	assert.True(t, strings.HasPrefix(queries[0], "SELECT TOP 1 "), "%s", queries[0])
+	assert.True(t, strings.HasPrefix(queries[1], "SELECT "), "%s", queries[1])
+	assert.NotContains(t, queries[1], "TOP")
+	assert.True(t, strings.HasSuffix(queries[1], " OFFSET 1 ROWS"), "%s", queries[1])
+	assert.NotContains(t, queries[2], "TOP")
+	assert.True(t, strings.HasSuffix(queries[2], " OFFSET 0 ROWS FETCH NEXT 1 ROWS ONLY"), "%s", queries[2])
+}
+
+func TestSelectEach(t *testing.T) {
+	db := setupDB(t)
+	defer teardown(t, db)
+
+	t.Run("All", func(t *testing.T) {
+		var ids []int32
+		err := db.SelectEach(PersonTable, func(str reform.Struct) error {
+			ids = append(ids, str.(*Person).ID)
+			return nil
+		}, "", where.Where(where.In(PersonTable.C.ID, 101, 102, 103)).OrderBy(PersonTable.C.ID))
+		require.NoError(t, err)
+		assert.Equal(t, []int32{101, 102, 103}, ids)
+	})
+
+	t.Run("Stop", func(t *testing.T) {
+		errStop := errors.New("stop")
+		var ids []int32
+		err := db.SelectEach(PersonTable, func(str reform.Struct) error {
+			ids = append(ids, str.(*Person).ID)
+			return errStop
+		}, "", where.OrderBy(PersonTable.C.ID))
+		assert.Equal(t, errStop, err)
+		assert.Equal(t, []int32{1}, ids)
+	})
+
+	t.Run("QueryError", func(t *testing.T) {
+		err := db.SelectEach(PersonTable, func(str reform.Struct) error {
+			t.Fatal("unexpected call")
+			return nil
+		}, "WHERE no_such_column = 1")
+		assert.Error(t, err)
+	})
+}
+
+func TestIter(t *testing.T) {
+	db := setupDB(t)
+	defer teardown(t, db)
+
+	t.Run("All", func(t *testing.T) {
+		var ids []int32
+		for str, err := range db.Iter(PersonTable, "", where.Where(where.In(PersonTable.C.ID, 101, 102, 103)).OrderBy(PersonTable.C.ID)) {
+			require.NoError(t, err)
+			ids = append(ids, str.(*Person).ID)
+		}
+		assert.Equal(t, []int32{101, 102, 103}, ids)
+	})
+
+	t.Run("Break", func(t *testing.T) {
+		var ids []int32
+		for str, err := range db.Iter(PersonTable, "", where.OrderBy(PersonTable.C.ID)) {
+			require.NoError(t, err)
+			ids = append(ids, str.(*Person).ID)
+			break
+		}
+		assert.Equal(t, []int32{1}, ids)
+
+		// connection is released
+		stats := db.DBInterface().(*sql.DB).Stats()
+		assert.Equal(t, 0, stats.InUse)
+	})
+
+	t.Run("QueryError", func(t *testing.T) {
+		var n int
+		for str, err := range db.Iter(PersonTable, "WHERE no_such_column = 1") {
+			assert.Nil(t, str)
+			assert.Error(t, err)
+			n++
+		}
+		assert.Equal(t, 1, n)
+	})
+}
+
+func TestSelectPage(t *testing.T) {
+	db := setupDB(t)
+	defer teardown(t, db)
+
+	// selectPages returns IDs of all people selected page by page, and the number of pages
+	selectPages := func(t *testing.T, orderBy []reform.PageOrder, limit int, filter string, args ...interface{}) (ids []int32, pages int) {
+		t.Helper()
+
+		var after string
+		for {
+			structs, next, err := db.SelectPage(PersonTable, reform.PageRequest{
+				OrderBy: orderBy,
+				After:   after,
+				Limit:   limit,
+				Where:   filter,
+				Args:    args,
+			})
+			require.NoError(t, err)
+			require.True(t, len(structs) <= limit)
+			for _, str := range structs {
+				ids = append(ids, str.(*Person).ID)
+			}
+			pages++
+
+			if next == "" {
+				return
+			}
+			after = next
+		}
+	}
+
+	// expectedIDs returns IDs of all people selected with given tail
+	expectedIDs := func(t *testing.T, tail string) []int32 {
+		t.Helper()
+
+		structs, err := db.SelectAllFrom(PersonTable, tail)
+		require.NoError(t, err)
+		ids := make([]int32, len(structs))
+		for i, str := range structs {
+			ids[i] = str.(*Person).ID
+		}
+		return ids
+	}
+
+	t.Run("PK", func(t *testing.T) {
+		expected := expectedIDs(t, "ORDER BY id")
+		ids, pages := selectPages(t, nil, 2, "")
+		assert.Equal(t, expected, ids)
+		assert.Equal(t, (len(expected)+1)/2, pages)
+	})
+
+	t.Run("NameDesc", func(t *testing.T) {
+		expected := expectedIDs(t, "ORDER BY name DESC, id")
+		orderBy := []reform.PageOrder{{Column: PersonTable.C.Name, Desc: true}}
+		for _, limit := range []int{1, 2, 100} {
+			ids, _ := selectPages(t, orderBy, limit, "")
+			assert.Equal(t, expected, ids, "limit %d", limit)
+		}
+	})
+
+	t.Run("Filter", func(t *testing.T) {
+		expected := expectedIDs(t, "WHERE email IS NOT NULL OR id = 102 ORDER BY name DESC, id")
+		require.NotEmpty(t, expected)
+		orderBy := []reform.PageOrder{{Column: PersonTable.C.Name, Desc: true}}
+		for _, limit := range []int{1, 2, 100} {
+			filter := "email IS NOT NULL OR id = " + db.Placeholder(1)
+			ids, _ := selectPages(t, orderBy, limit, filter, 102)
+			assert.Equal(t, expected, ids, "limit %d", limit)
+
+			cond := where.Or(where.IsNotNull(PersonTable.C.Email), where.Eq(PersonTable.C.ID, 102))
+			ids, _ = selectPages(t, orderBy, limit, "", cond)
+			assert.Equal(t, expected, ids, "limit %d", limit)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		_, _, err := db.SelectPage(PersonTable, reform.PageRequest{})
+		assert.EqualError(t, err, "reform: page limit should be positive, got 0")
+
+		_, _, err = db.SelectPage(PersonTable, reform.PageRequest{
+			OrderBy: []reform.PageOrder{{Column: "no_such_column"}},
+			Limit:   1,
+		})
+		assert.EqualError(t, err, "reform: unexpected columns: [no_such_column]")
+
+		_, next, err := db.SelectPage(PersonTable, reform.PageRequest{Limit: 1})
+		require.NoError(t, err)
+		require.NotEmpty(t, next)
+
+		for _, after := range []string{"garbage", "e30"} {
+			_, _, err = db.SelectPage(PersonTable, reform.PageRequest{After: after, Limit: 1})
+			assert.Equal(t, reform.ErrInvalidPageCursor, err, "%q", after)
+		}
+
+		_, _, err = db.SelectPage(PersonTable, reform.PageRequest{
+			OrderBy: []reform.PageOrder{{Column: PersonTable.C.ID, Desc: true}},
+			After:   next,
+			Limit:   1,
+		})
+		assert.Equal(t, reform.ErrInvalidPageCursor, err)
+	})
+}
+
+func TestSelectJoined(t *testing.T) {
+	db := setupDB(t)
+	defer teardown(t, db)
+
+	people := db.QualifiedView(PersonTable)
+	pp := db.QualifiedView(PersonProjectView)
+	projects := db.QualifiedView(ProjectTable)
+	col := func(view, column string) string {
+		return view + "." + db.QuoteIdentifier(column)
+	}
+	tail := "FROM " + people +
+		" LEFT JOIN " + pp + " ON " + col(pp, "person_id") + " = " + col(people, "id") +
+		" LEFT JOIN " + projects + " ON " + col(projects, "id") + " = " + col(pp, "project_id")
+	views := []reform.View{PersonTable, PersonProjectView, ProjectTable}
+
+	t.Run("LeftJoin", func(t *testing.T) {
+		rows, err := db.SelectJoined(views, tail+" WHERE "+col(people, "id")+" IN ("+strings.Join(db.Placeholders(1, 2), ", ")+")"+
+			" ORDER BY "+col(people, "id")+", "+col(projects, "id"), 1, 102)
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+
+		require.NotNil(t, rows[0][0])
+		assert.Equal(t, int32(1), rows[0][0].(*Person).ID)
+		assert.Nil(t, rows[0][1])
+		assert.Nil(t, rows[0][2])
+
+		for i, id := range []string{"baron", "queen"} {
+			row := rows[i+1]
+			require.Len(t, row, 3)
+			assert.Equal(t, int32(102), row[0].(*Person).ID)
+			assert.Equal(t, &PersonProject{PersonID: 102, ProjectID: id}, row[1])
+			project := row[2].(*Project)
+			assert.Equal(t, id, project.ID)
+			assert.Equal(t, time.UTC, project.Start.Location(), "AfterFind should be called")
+		}
+	})
+
+	t.Run("NextRowMulti", func(t *testing.T) {
+		rows, err := db.SelectJoinedRows(views[:2], tail, where.Where(where.Eq(PersonProjectView.C.PersonID, 101)))
+		require.NoError(t, err)
+		defer rows.Close()
+
+		var person Person
+		var personProject PersonProject
+		strs, err := db.NextRowMulti(rows, &person, &personProject)
+		require.NoError(t, err)
+		assert.Equal(t, []reform.Struct{&person, &personProject}, strs)
+		assert.Equal(t, int32(101), person.ID)
+		assert.Equal(t, int32(101), personProject.PersonID)
+
+		_, err = db.NextRowMulti(rows, &person, &personProject)
+		assert.Equal(t, reform.ErrNoRows, err)
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		rows, err := db.SelectJoined(views, tail+" WHERE 1 = 0")
+		assert.NoError(t, err)
+		assert.Nil(t, rows)
+	})
+}
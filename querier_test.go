@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"database/sql/driver"
+	"errors"
 	"fmt"
 	"testing"
 	"time"
@@ -67,6 +68,12 @@ func TestExecWithContext(t *testing.T) {
 	_, err := q.Exec(query)
 	dur := time.Since(start)
 
+	// check wrapped error
+	var queryErr *reform.QueryError
+	require.True(t, errors.As(err, &queryErr), "%T", err)
+	assert.Equal(t, query, queryErr.Query)
+	err = errors.Unwrap(err)
+
 	switch dbDriver.(type) {
 	case *sqlite3Driver.SQLiteDriver:
 		// sqlite3 driver does not support query cancelation
@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/mc2soft/reform"
@@ -78,8 +79,8 @@ func convertName(sqlName string) string {
 	return strings.Join(res, "")
 }
 
-// getPrimaryKeyColumn returns single primary key column for given table, or nil.
-func getPrimaryKeyColumn(db *reform.DB, catalog, schema, tableName string) *keyColumnUsage {
+// getPrimaryKeyColumns returns primary key columns for given table, or nil.
+func getPrimaryKeyColumns(db *reform.DB, catalog, schema, tableName string) []keyColumnUsage {
 	using := []string{
 		"table_catalog", "table_schema", "table_name",
 		"constraint_catalog", "constraint_schema", "constraint_name",
@@ -98,7 +99,7 @@ func getPrimaryKeyColumn(db *reform.DB, catalog, schema, tableName string) *keyC
 				key_column_usage.table_schema = %s AND
 				key_column_usage.table_name = %s AND
 				constraint_type = 'PRIMARY KEY'
-			ORDER BY ordinal_position DESC`,
+			ORDER BY ordinal_position`,
 		strings.Join(using, " AND "), db.Placeholder(1), db.Placeholder(2), db.Placeholder(3),
 	)
 	rows, err := db.Query(q, catalog, schema, tableName)
@@ -107,34 +108,70 @@ func getPrimaryKeyColumn(db *reform.DB, catalog, schema, tableName string) *keyC
 	}
 	defer rows.Close() //nolint:errcheck
 
-	var key keyColumnUsage
-	var count int
+	var keys []keyColumnUsage
 	for {
+		var key keyColumnUsage
 		if err = db.NextRow(&key, rows); err != nil {
 			break
 		}
-		count++
 		logger.Debugf("%s", key)
+		if key.OrdinalPosition != len(keys)+1 {
+			logger.Fatalf("Unexpected ordinal position %d for table %s. Please report this bug.", key.OrdinalPosition, tableName)
+		}
+		keys = append(keys, key)
 	}
 	if err != reform.ErrNoRows {
 		logger.Fatalf("%s", err)
 	}
-	switch count {
-	case 0:
-		return nil
-	case 1:
-		if key.OrdinalPosition > 1 {
-			logger.Printf(
-				"Composite primary keys are not supported (found %d columns), skipping it for table %s.",
-				key.OrdinalPosition, tableName,
-			)
-			return nil
-		}
-		return &key
-	default:
-		logger.Fatalf("Too many rows found. Please report this bug.")
-		return nil
+	return keys
+}
+
+// setPKFieldIndexes sets primary key field indexes for fields with given primary key columns in key order,
+// and returns field comments in the same order as fields.
+// Package parse orders composite primary key fields by their position in struct, so if key order differs
+// from columns order in table, primary key fields are moved first in key order.
+func setPKFieldIndexes(str *parse.StructInfo, comments []string, keys []string) []string {
+	fields := make(map[string]int, len(str.Fields))
+	for i, f := range str.Fields {
+		fields[f.Column] = i
+	}
+	pks := make([]int, 0, len(keys))
+	for _, k := range keys {
+		if i, ok := fields[k]; ok {
+			pks = append(pks, i)
+		}
+	}
+	if len(pks) == 0 {
+		return comments
 	}
+
+	if !sort.IntsAreSorted(pks) {
+		order := append([]int(nil), pks...)
+		isPK := make(map[int]struct{}, len(pks))
+		for i, pk := range pks {
+			isPK[pk] = struct{}{}
+			pks[i] = i
+		}
+		for i := range str.Fields {
+			if _, ok := isPK[i]; !ok {
+				order = append(order, i)
+			}
+		}
+
+		fieldsInOrder := make([]parse.FieldInfo, len(order))
+		commentsInOrder := make([]string, len(order))
+		for j, i := range order {
+			fieldsInOrder[j] = str.Fields[i]
+			commentsInOrder[j] = comments[i]
+		}
+		str.Fields, comments = fieldsInOrder, commentsInOrder
+	}
+
+	str.PKFieldIndex = pks[0]
+	if len(pks) > 1 {
+		str.PKFieldIndexes = pks
+	}
+	return comments
 }
 
 // initModelsInformationSchema returns structs from database with information_schema.
@@ -154,7 +191,11 @@ func initModelsInformationSchema(db *reform.DB, tablesTail string, typeFunc type
 		}
 		var comments []string
 
-		key := getPrimaryKeyColumn(db, table.TableCatalog, table.TableSchema, table.TableName)
+		keys := getPrimaryKeyColumns(db, table.TableCatalog, table.TableSchema, table.TableName)
+		pkColumns := make([]string, len(keys))
+		for i, key := range keys {
+			pkColumns[i] = key.ColumnName
+		}
 
 		tail := fmt.Sprintf(
 			`WHERE table_catalog = %s AND table_schema = %s AND table_name = %s ORDER BY ordinal_position`,
@@ -164,7 +205,7 @@ func initModelsInformationSchema(db *reform.DB, tablesTail string, typeFunc type
 		if err != nil {
 			logger.Fatalf("%s", err)
 		}
-		for i, c := range columns {
+		for _, c := range columns {
 			column := c.(*column)
 			typ, pack, comment := typeFunc(column.Type, bool(column.IsNullable))
 			if pack != "" {
@@ -176,11 +217,8 @@ func initModelsInformationSchema(db *reform.DB, tablesTail string, typeFunc type
 				Type:   typ,
 				Column: column.Name,
 			})
-
-			if key != nil && key.ColumnName == column.Name {
-				str.PKFieldIndex = i
-			}
 		}
+		comments = setPKFieldIndexes(&str, comments, pkColumns)
 
 		structs = append(structs, StructData{
 			Imports:       imports,
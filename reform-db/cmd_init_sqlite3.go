@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/mc2soft/reform"
@@ -93,6 +94,8 @@ func initModelsSQLite3(db *reform.DB) (structs []StructData) {
 			PKFieldIndex: -1,
 		}
 		var comments []string
+		var pkColumns []string
+		pkOrder := make(map[string]int) // 1-based index in primary key
 
 		rows, err := db.Query("PRAGMA table_info(" + tableName + ")") // no placeholders for PRAGMA
 		if err != nil {
@@ -105,8 +108,9 @@ func initModelsSQLite3(db *reform.DB) (structs []StructData) {
 			if err = db.NextRow(&column, rows); err != nil {
 				break
 			}
-			if column.PK {
-				str.PKFieldIndex = len(str.Fields)
+			if column.PK > 0 {
+				pkColumns = append(pkColumns, column.Name)
+				pkOrder[column.Name] = column.PK
 			}
 			typ, pack, comment := goTypeSQLite3(column.Type, !column.NotNull)
 			if pack != "" {
@@ -125,6 +129,8 @@ func initModelsSQLite3(db *reform.DB) (structs []StructData) {
 		if err = rows.Close(); err != nil {
 			logger.Fatalf("%s", err)
 		}
+		sort.Slice(pkColumns, func(i, j int) bool { return pkOrder[pkColumns[i]] < pkOrder[pkColumns[j]] })
+		comments = setPKFieldIndexes(&str, comments, pkColumns)
 
 		structs = append(structs, StructData{
 			Imports:       imports,
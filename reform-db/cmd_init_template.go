@@ -13,6 +13,19 @@ type StructData struct {
 	FieldComments []string
 }
 
+// IsPK returns true if field with given index is a part of primary key.
+func (sd StructData) IsPK(i int) bool {
+	if sd.PKFieldIndexes == nil {
+		return i == sd.PKFieldIndex
+	}
+	for _, pk := range sd.PKFieldIndexes {
+		if pk == i {
+			return true
+		}
+	}
+	return false
+}
+
 var (
 	prologTemplate = template.Must(template.New("prolog").Parse(`
 import (
@@ -29,7 +42,7 @@ import (
 //reform:{{ .SQLName }}
 type {{ .Type }} struct {
 	{{- range $i, $f := .Fields }}
-    {{ $f.Name }} {{ $f.Type }} ` + "`" + `reform:"{{ $f.Column }}{{ if eq $i $.PKFieldIndex }},pk{{ end }}"` + "`" + ` {{ index $.FieldComments $i }}
+    {{ $f.Name }} {{ $f.Type }} ` + "`" + `reform:"{{ $f.Column }}{{ if $.IsPK $i }},pk{{ end }}"` + "`" + ` {{ index $.FieldComments $i }}
 	{{- end }}
 }
 `))
@@ -13,23 +13,35 @@ import (
 func (s *ReformDBSuite) TestInit() {
 	good, err := parse.File("../internal/test/models/good.go")
 	s.Require().NoError(err)
-	s.Require().Len(good, 6)
+	s.Require().Len(good, 8)
 
 	people := good[0]
 	projects := good[1]
 	personProject := good[2]
 	idOnly := good[3]
 	constraints := good[4]
+	compositePK := good[6]
+	document := good[7]
 
 	// patch difference we don't handle
 	people.Type = strings.Replace(people.Type, "Person", "People", -1)
 	projects.Type = strings.Replace(projects.Type, "Project", "Projects", -1)
+	compositePK.Type = strings.Replace(compositePK.Type, "PK", "Pk", -1)
+	document.Type = strings.Replace(document.Type, "Document", "Documents", -1)
+	document.Fields[2].Version = false
+	document.Fields[3].SoftDelete = false
+	document.Fields[4].AutoCreate = false
+	document.Fields[5].AutoUpdate = false
+	personProject.Fields[0].FK = ""
+	personProject.Fields[1].FK = ""
 	if s.db.Dialect == sqlite3.Dialect {
 		people.Fields[0].Type = strings.Replace(people.Fields[0].Type, "int32", "int64", -1)
 		people.Fields[1].Type = strings.Replace(people.Fields[1].Type, "int32", "int64", -1)
 		personProject.Fields[0].Type = strings.Replace(personProject.Fields[0].Type, "int32", "int64", -1)
 		idOnly.Fields[0].Type = strings.Replace(idOnly.Fields[0].Type, "int32", "int64", -1)
 		constraints.Fields[0].Type = strings.Replace(constraints.Fields[0].Type, "int32", "int64", -1)
+		compositePK.Fields[0].Type = strings.Replace(compositePK.Fields[0].Type, "int32", "int64", -1)
+		document.Fields[0].Type = strings.Replace(document.Fields[0].Type, "int32", "int64", -1)
 	}
 
 	dir, err := ioutil.TempDir("", "ReformDBTestInit")
@@ -40,7 +52,7 @@ func (s *ReformDBSuite) TestInit() {
 
 	fis, err := ioutil.ReadDir(dir)
 	s.Require().NoError(err)
-	s.Require().Len(fis, 5)
+	s.Require().Len(fis, 7)
 
 	ff := filepath.Join(dir, "people.go")
 	actual, err := parse.File(ff)
@@ -72,6 +84,50 @@ func (s *ReformDBSuite) TestInit() {
 	s.Require().Len(actual, 1)
 	s.Require().Equal(constraints, actual[0])
 
+	ff = filepath.Join(dir, "composite_pk.go")
+	actual, err = parse.File(ff)
+	s.Require().NoError(err)
+	s.Require().Len(actual, 1)
+	s.Require().Equal(compositePK, actual[0])
+
+	ff = filepath.Join(dir, "documents.go")
+	actual, err = parse.File(ff)
+	s.Require().NoError(err)
+	s.Require().Len(actual, 1)
+	s.Require().Equal(document, actual[0])
+
 	err = os.RemoveAll(dir)
 	s.Require().NoError(err)
 }
+
+func (s *ReformDBSuite) TestSetPKFieldIndexes() {
+	fields := []parse.FieldInfo{
+		{Name: "A", Type: "int32", Column: "a"},
+		{Name: "B", Type: "string", Column: "b"},
+		{Name: "C", Type: "string", Column: "c"},
+	}
+
+	str := parse.StructInfo{Fields: append([]parse.FieldInfo(nil), fields...), PKFieldIndex: -1}
+	comments := setPKFieldIndexes(&str, []string{"// a", "// b", "// c"}, []string{"a", "c"})
+	s.Equal(fields, str.Fields)
+	s.Equal([]string{"// a", "// b", "// c"}, comments)
+	s.Equal(0, str.PKFieldIndex)
+	s.Equal([]int{0, 2}, str.PKFieldIndexes)
+
+	// fields are reordered for key order which differs from columns order
+	str = parse.StructInfo{Fields: append([]parse.FieldInfo(nil), fields...), PKFieldIndex: -1}
+	comments = setPKFieldIndexes(&str, []string{"// a", "// b", "// c"}, []string{"c", "a"})
+	s.Equal([]parse.FieldInfo{fields[2], fields[0], fields[1]}, str.Fields)
+	s.Equal([]string{"// c", "// a", "// b"}, comments)
+	s.Equal(0, str.PKFieldIndex)
+	s.Equal([]int{0, 1}, str.PKFieldIndexes)
+
+	str = parse.StructInfo{Fields: append([]parse.FieldInfo(nil), fields...), PKFieldIndex: -1}
+	setPKFieldIndexes(&str, []string{"", "", ""}, []string{"b"})
+	s.Equal(1, str.PKFieldIndex)
+	s.Nil(str.PKFieldIndexes)
+
+	str = parse.StructInfo{Fields: append([]parse.FieldInfo(nil), fields...), PKFieldIndex: -1}
+	setPKFieldIndexes(&str, []string{"", "", ""}, nil)
+	s.Equal(-1, str.PKFieldIndex)
+}
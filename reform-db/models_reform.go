@@ -10,9 +10,20 @@ import (
 	"github.com/mc2soft/reform/parse"
 )
 
+// tableColumns contains column descriptors of tables view or table.
+type tableColumns struct {
+	TableCatalog reform.Column
+	TableSchema  reform.Column
+	TableName    reform.Column
+	TableType    reform.Column
+}
+
 type tableViewType struct {
 	s parse.StructInfo
 	z []interface{}
+
+	// C contains column descriptors of that view or table.
+	C tableColumns
 }
 
 // Schema returns a schema name in SQL database ("information_schema").
@@ -55,6 +66,12 @@ var tableView = &tableViewType{
 		PKFieldIndex: -1,
 	},
 	z: new(table).Values(),
+	C: tableColumns{
+		TableCatalog: "table_catalog",
+		TableSchema:  "table_schema",
+		TableName:    "table_name",
+		TableType:    "table_type",
+	},
 }
 
 // String returns a string representation of this struct or record.
@@ -101,9 +118,22 @@ var (
 	_ fmt.Stringer  = (*table)(nil)
 )
 
+// columnColumns contains column descriptors of columns view or table.
+type columnColumns struct {
+	TableCatalog reform.Column
+	TableSchema  reform.Column
+	TableName    reform.Column
+	Name         reform.Column
+	IsNullable   reform.Column
+	Type         reform.Column
+}
+
 type columnViewType struct {
 	s parse.StructInfo
 	z []interface{}
+
+	// C contains column descriptors of that view or table.
+	C columnColumns
 }
 
 // Schema returns a schema name in SQL database ("information_schema").
@@ -150,6 +180,14 @@ var columnView = &columnViewType{
 		PKFieldIndex: -1,
 	},
 	z: new(column).Values(),
+	C: columnColumns{
+		TableCatalog: "table_catalog",
+		TableSchema:  "table_schema",
+		TableName:    "table_name",
+		Name:         "column_name",
+		IsNullable:   "is_nullable",
+		Type:         "data_type",
+	},
 }
 
 // String returns a string representation of this struct or record.
@@ -202,9 +240,18 @@ var (
 	_ fmt.Stringer  = (*column)(nil)
 )
 
+// keyColumnUsageColumns contains column descriptors of key_column_usage view or table.
+type keyColumnUsageColumns struct {
+	ColumnName      reform.Column
+	OrdinalPosition reform.Column
+}
+
 type keyColumnUsageViewType struct {
 	s parse.StructInfo
 	z []interface{}
+
+	// C contains column descriptors of that view or table.
+	C keyColumnUsageColumns
 }
 
 // Schema returns a schema name in SQL database ("information_schema").
@@ -243,6 +290,10 @@ var keyColumnUsageView = &keyColumnUsageViewType{
 		PKFieldIndex: -1,
 	},
 	z: new(keyColumnUsage).Values(),
+	C: keyColumnUsageColumns{
+		ColumnName:      "column_name",
+		OrdinalPosition: "ordinal_position",
+	},
 }
 
 // String returns a string representation of this struct or record.
@@ -283,9 +334,17 @@ var (
 	_ fmt.Stringer  = (*keyColumnUsage)(nil)
 )
 
+// sqliteMasterColumns contains column descriptors of sqlite_master view or table.
+type sqliteMasterColumns struct {
+	Name reform.Column
+}
+
 type sqliteMasterViewType struct {
 	s parse.StructInfo
 	z []interface{}
+
+	// C contains column descriptors of that view or table.
+	C sqliteMasterColumns
 }
 
 // Schema returns a schema name in SQL database ("").
@@ -321,6 +380,9 @@ var sqliteMasterView = &sqliteMasterViewType{
 		PKFieldIndex: -1,
 	},
 	z: new(sqliteMaster).Values(),
+	C: sqliteMasterColumns{
+		Name: "name",
+	},
 }
 
 // String returns a string representation of this struct or record.
@@ -358,9 +420,22 @@ var (
 	_ fmt.Stringer  = (*sqliteMaster)(nil)
 )
 
+// sqliteTableInfoColumns contains column descriptors of dummy view or table.
+type sqliteTableInfoColumns struct {
+	CID          reform.Column
+	Name         reform.Column
+	Type         reform.Column
+	NotNull      reform.Column
+	DefaultValue reform.Column
+	PK           reform.Column
+}
+
 type sqliteTableInfoViewType struct {
 	s parse.StructInfo
 	z []interface{}
+
+	// C contains column descriptors of that view or table.
+	C sqliteTableInfoColumns
 }
 
 // Schema returns a schema name in SQL database ("").
@@ -401,11 +476,19 @@ var sqliteTableInfoView = &sqliteTableInfoViewType{
 			{Name: "Type", Type: "string", Column: "type"},
 			{Name: "NotNull", Type: "bool", Column: "notnull"},
 			{Name: "DefaultValue", Type: "*string", Column: "dflt_value"},
-			{Name: "PK", Type: "bool", Column: "pk"},
+			{Name: "PK", Type: "int", Column: "pk"},
 		},
 		PKFieldIndex: -1,
 	},
 	z: new(sqliteTableInfo).Values(),
+	C: sqliteTableInfoColumns{
+		CID:          "cid",
+		Name:         "name",
+		Type:         "type",
+		NotNull:      "notnull",
+		DefaultValue: "dflt_value",
+		PK:           "pk",
+	},
 }
 
 // String returns a string representation of this struct or record.
@@ -26,6 +26,22 @@ var (
 	versionF = flag.Bool("version", false, "Print version and exit")
 )
 
+// unexported returns unexported Go identifier for given exported type name,
+// lowercasing leading initialism as a whole: "Person" -> "person", "IDOnly" -> "idOnly", "URL" -> "url".
+func unexported(name string) string {
+	n := 0
+	for n < len(name) && name[n] >= 'A' && name[n] <= 'Z' {
+		n++
+	}
+	if n > 1 && n < len(name) && name[n] >= 'a' && name[n] <= 'z' {
+		n-- // the last uppercase letter starts the next word
+	}
+	if n == 0 {
+		n = 1
+	}
+	return strings.ToLower(name[:n]) + name[n:]
+}
+
 func processFile(path, file, pack string) error {
 	logger.Debugf("processFile: path=%q file=%q pack=%q", path, file, pack)
 
@@ -60,7 +76,7 @@ func processFile(path, file, pack string) error {
 	sds := make([]StructData, 0, len(structs))
 	for _, str := range structs {
 		// decide about view/table suffix
-		t := strings.ToLower(str.Type[0:1]) + str.Type[1:]
+		t := unexported(str.Type)
 		v := str.Type
 		if str.IsTable() {
 			t += "TableType"
@@ -71,9 +87,10 @@ func processFile(path, file, pack string) error {
 		}
 
 		sd := StructData{
-			StructInfo: str,
-			TableType:  t,
-			TableVar:   v,
+			StructInfo:  str,
+			TableType:   t,
+			TableVar:    v,
+			ColumnsType: unexported(str.Type) + "Columns",
 		}
 		sds = append(sds, sd)
 
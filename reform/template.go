@@ -9,8 +9,9 @@ import (
 // StructData represents struct info for XXX_reform.go file generation.
 type StructData struct {
 	parse.StructInfo
-	TableType string
-	TableVar  string
+	TableType   string
+	TableVar    string
+	ColumnsType string
 }
 
 //nolint:gochecknoglobals
@@ -26,9 +27,19 @@ import (
 `))
 
 	structTemplate = template.Must(template.New("struct").Parse(`
+// {{ .ColumnsType }} contains column descriptors of {{ .SQLName }} view or table.
+type {{ .ColumnsType }} struct {
+	{{- range .Fields }}
+	{{ .Name }} reform.Column
+	{{- end }}
+}
+
 type {{ .TableType }} struct {
 	s parse.StructInfo
 	z []interface{}
+
+	// C contains column descriptors of that view or table.
+	C {{ .ColumnsType }}
 }
 
 // Schema returns a schema name in SQL database ("{{ .SQLSchema }}").
@@ -51,6 +62,19 @@ func (v *{{ .TableType }}) NewStruct() reform.Struct {
 	return new({{ .Type }})
 }
 
+{{- if .FKFields }}
+
+// ForeignKeys returns a new slice of foreign keys of that view or table.
+func (v *{{ .TableType }}) ForeignKeys() []reform.ForeignKey {
+	return []reform.ForeignKey{
+		{{- range .FKFields }}
+		{Column: {{ printf "%q" .Column }}, RefTable: {{ printf "%q" .FKTable }}, RefColumn: {{ printf "%q" .FKColumn }}},
+		{{- end }}
+	}
+}
+
+{{- end }}
+
 {{- if .IsTable }}
 
 // NewRecord makes a new record for that table.
@@ -63,12 +87,66 @@ func (v *{{ .TableType }}) PKColumnIndex() uint {
 	return uint(v.s.PKFieldIndex)
 }
 
+// PKColumnIndexes returns a new slice of indexes of all primary key columns for that table in SQL database.
+func (v *{{ .TableType }}) PKColumnIndexes() []uint {
+{{- if .IsCompositePK }}
+	res := make([]uint, len(v.s.PKFieldIndexes))
+	for i, pk := range v.s.PKFieldIndexes {
+		res[i] = uint(pk)
+	}
+	return res
+{{- else }}
+	return []uint{uint(v.s.PKFieldIndex)}
+{{- end }}
+}
+
+{{- if ge .VersionFieldIndex 0 }}
+
+// VersionColumnIndex returns an index of version column for that table in SQL database.
+func (v *{{ .TableType }}) VersionColumnIndex() uint {
+	return uint(v.s.VersionFieldIndex())
+}
+
+{{- end }}
+
+{{- if ge .SoftDeleteFieldIndex 0 }}
+
+// SoftDeleteColumnIndex returns an index of soft delete column for that table in SQL database.
+func (v *{{ .TableType }}) SoftDeleteColumnIndex() uint {
+	return {{ .SoftDeleteFieldIndex }}
+}
+
+{{- end }}
+
+{{- if ge .AutoCreateFieldIndex 0 }}
+
+// AutoCreateColumnIndex returns an index of column set on insert for that table in SQL database.
+func (v *{{ .TableType }}) AutoCreateColumnIndex() uint {
+	return {{ .AutoCreateFieldIndex }}
+}
+
+{{- end }}
+
+{{- if ge .AutoUpdateFieldIndex 0 }}
+
+// AutoUpdateColumnIndex returns an index of column set on update for that table in SQL database.
+func (v *{{ .TableType }}) AutoUpdateColumnIndex() uint {
+	return {{ .AutoUpdateFieldIndex }}
+}
+
+{{- end }}
+
 {{- end }}
 
 // {{ .TableVar }} represents {{ .SQLName }} view or table in SQL database.
 var {{ .TableVar }} = &{{ .TableType }} {
 	s: {{ .GoString }},
 	z: new({{ .Type }}).Values(),
+	C: {{ .ColumnsType }}{
+		{{- range .Fields }}
+		{{ .Name }}: {{ printf "%q" .Column }},
+		{{- end }}
+	},
 }
 
 // String returns a string representation of this struct or record.
@@ -108,6 +186,39 @@ func (s *{{ .Type }}) Table() reform.Table {
 	return {{ .TableVar }}
 }
 
+{{- if .IsCompositePK }}
+
+// PKValue returns a slice of values of composite primary key for that record.
+// Returned interface{} value is never untyped nil.
+func (s *{{ .Type }}) PKValue() interface{} {
+	return []interface{}{ {{- range .PKFields }}
+		s.{{ .Name }}, {{- end }}
+	}
+}
+
+// PKPointer returns a slice of pointers to composite primary key fields for that record.
+// Returned interface{} value is never untyped nil.
+func (s *{{ .Type }}) PKPointer() interface{} {
+	return []interface{}{ {{- range .PKFields }}
+		&s.{{ .Name }}, {{- end }}
+	}
+}
+
+// HasPK returns true if record has at least one composite primary key field set to non-zero value, false otherwise.
+func (s *{{ .Type }}) HasPK() bool {
+	return {{ range $i, $f := .PKFields }}{{ if $i }} ||
+		{{ end }}s.{{ $f.Name }} != {{ $.TableVar }}.z[{{ $.TableVar }}.s.PKFieldIndexes[{{ $i }}]]{{ end }}
+}
+
+// SetPK sets record composite primary key from a slice of values, if possible.
+//
+// Deprecated: prefer direct field assignment where possible.
+func (s *{{ .Type }}) SetPK(pk interface{}) {
+	reform.SetPK(s, pk)
+}
+
+{{- else }}
+
 // PKValue returns a value of primary key for that record.
 // Returned interface{} value is never untyped nil.
 func (s *{{ .Type }}) PKValue() interface{} {
@@ -134,13 +245,36 @@ func (s *{{ .Type }}) SetPK(pk interface{}) {
 
 {{- end }}
 
+// Changed returns names of columns which values differ from the snapshot of that record
+// taken when it was loaded or saved, or all columns if it doesn't embed reform.Snapshot.
+func (s *{{ .Type }}) Changed() []string {
+	return reform.ChangedColumns(s)
+}
+
+{{- end }}
+
 // check interfaces
 var (
 	_ reform.View   = {{ .TableVar }}
 	_ reform.Struct = (*{{ .Type }})(nil)
+{{- if .FKFields }}
+	_ reform.ForeignKeysView = {{ .TableVar }}
+{{- end }}
 {{- if .IsTable }}
 	_ reform.Table  = {{ .TableVar }}
 	_ reform.Record = (*{{ .Type }})(nil)
+{{- if ge .VersionFieldIndex 0 }}
+	_ reform.VersionedTable = {{ .TableVar }}
+{{- end }}
+{{- if ge .SoftDeleteFieldIndex 0 }}
+	_ reform.SoftDeleteTable = {{ .TableVar }}
+{{- end }}
+{{- if ge .AutoCreateFieldIndex 0 }}
+	_ reform.AutoCreateTable = {{ .TableVar }}
+{{- end }}
+{{- if ge .AutoUpdateFieldIndex 0 }}
+	_ reform.AutoUpdateTable = {{ .TableVar }}
+{{- end }}
 {{- end }}
 	_ fmt.Stringer  = (*{{ .Type }})(nil)
 )
@@ -0,0 +1,496 @@
+package reform
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// ReplicaSelection is a strategy of choosing one of healthy replicas for a query.
+type ReplicaSelection int
+
+// Replica selection strategies.
+const (
+	WeightedRandom ReplicaSelection = iota // random replica with probability proportional to its weight (default)
+	RoundRobin                             // replicas in turn, weights are ignored
+	LeastLatency                           // replica with the least average latency of queries and pings
+)
+
+// String returns a string representation of this strategy.
+func (s ReplicaSelection) String() string {
+	switch s {
+	case WeightedRandom:
+		return "WeightedRandom"
+	case RoundRobin:
+		return "RoundRobin"
+	case LeastLatency:
+		return "LeastLatency"
+	default:
+		return fmt.Sprintf("ReplicaSelection(%d)", int(s))
+	}
+}
+
+// ReplicaStats contains statistics of a single replica in ReplicaPool.
+type ReplicaStats struct {
+	Name      string
+	Weight    int
+	Healthy   bool
+	Queries   int64         // number of queries sent to replica
+	Errors    int64         // number of queries failed with connection-level errors, failed pings and lag probes
+	Fallbacks int64         // number of queries resent to master after connection-level errors
+	Latency   time.Duration // moving average of queries and pings latency
+	Lag       time.Duration // replication lag measured by the last health check (see SetMaxLag)
+	LastCheck time.Time     // time of the last health check, zero if there were none
+	LastError error         // the last connection-level, ping or lag probe error, nil if there were none
+	DBStats   sql.DBStats   // replica's connection pool statistics
+}
+
+// replica is a single replica in ReplicaPool. Fields are protected by pool's mutex.
+type replica struct {
+	name   string
+	db     *sql.DB
+	weight int
+
+	healthy   bool
+	queries   int64
+	errors    int64
+	fallbacks int64
+	latency   time.Duration
+	lag       time.Duration
+	lastCheck time.Time
+	lastError error
+}
+
+// observe updates replica's latency moving average.
+func (r *replica) observe(d time.Duration) {
+	if r.latency == 0 {
+		r.latency = d
+		return
+	}
+	r.latency = (4*r.latency + d) / 5
+}
+
+// ReplicaPool is a set of replica (slave) connections used by DB for queries routed to replicas
+// (see RoutingPolicy). All its methods are safe for concurrent use.
+//
+// By default, replicas are chosen with WeightedRandom strategy, all of them are considered healthy,
+// and connection-level errors are returned to the caller as is. Use StartHealthChecks to exclude
+// replicas which are not responding to pings, and SetFallback to resend failed queries to master.
+type ReplicaPool struct {
+	dialect Dialect
+
+	m         sync.Mutex
+	replicas  []*replica
+	selection ReplicaSelection
+	fallback  bool
+	maxLag    time.Duration
+	txMaster  bool
+	next      int
+	slaves    int
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+// newReplicaPool creates a new empty pool for given dialect.
+func newReplicaPool(dialect Dialect) *ReplicaPool {
+	return &ReplicaPool{
+		dialect: dialect,
+	}
+}
+
+// Add adds replica connection with given name and weight (which should be positive) to the pool.
+// It returns an error if replica with the same name is already present.
+func (p *ReplicaPool) Add(name string, db *sql.DB, weight int) error {
+	if weight <= 0 {
+		return fmt.Errorf("reform: invalid weight %d for replica %q", weight, name)
+	}
+
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	for _, r := range p.replicas {
+		if r.name == name {
+			return fmt.Errorf("reform: replica %q already exists", name)
+		}
+	}
+	p.replicas = append(p.replicas, &replica{
+		name:    name,
+		db:      db,
+		weight:  weight,
+		healthy: true,
+	})
+	return nil
+}
+
+// addSlaves adds replicas with generated names "slave1", "slave2", etc. and weight 1.
+func (p *ReplicaPool) addSlaves(dbs []*sql.DB) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	for _, db := range dbs {
+		p.slaves++
+		p.replicas = append(p.replicas, &replica{
+			name:    fmt.Sprintf("slave%d", p.slaves),
+			db:      db,
+			weight:  1,
+			healthy: true,
+		})
+	}
+}
+
+// Remove removes replica with given name from the pool and returns its connection,
+// or nil if there is no such replica. Connection is not closed.
+func (p *ReplicaPool) Remove(name string) *sql.DB {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	for i, r := range p.replicas {
+		if r.name == name {
+			p.replicas = append(p.replicas[:i:i], p.replicas[i+1:]...)
+			return r.db
+		}
+	}
+	return nil
+}
+
+// Len returns the number of replicas in the pool, both healthy and not.
+func (p *ReplicaPool) Len() int {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	return len(p.replicas)
+}
+
+// SetSelection sets replica selection strategy.
+func (p *ReplicaPool) SetSelection(selection ReplicaSelection) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	p.selection = selection
+}
+
+// SetFallback enables or disables resending queries to master when replica fails with
+// connection-level error (for example, when connection is refused or reset).
+// Statements executed with Exec are resent only if they were not sent to replica
+// (connection was refused, or driver returned driver.ErrBadConn), as replica may have already executed them.
+// Other errors (syntax errors, constraint violations, etc.) are always returned as is.
+func (p *ReplicaPool) SetFallback(fallback bool) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	p.fallback = fallback
+}
+
+// SetReadOnlyTransactionsOnMaster disables (if onMaster is true) or enables (default) starting
+// read-only transactions on replicas (see DB.BeginTx).
+func (p *ReplicaPool) SetReadOnlyTransactionsOnMaster(onMaster bool) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	p.txMaster = onMaster
+}
+
+// SetMaxLag sets the maximal replication lag of healthy replica. If it is positive and DB's Dialect
+// implements ReplicationLagProber, health checks (see StartHealthChecks) also measure replication lag,
+// and mark replicas which are lagging behind more than that, or which lag can't be measured, as unhealthy.
+// Zero value (default) disables lag probes.
+func (p *ReplicaPool) SetMaxLag(maxLag time.Duration) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	p.maxLag = maxLag
+}
+
+// SetWeight sets weight (which should be positive) of replica with given name.
+// It returns false if there is no such replica.
+func (p *ReplicaPool) SetWeight(name string, weight int) bool {
+	if weight <= 0 {
+		return false
+	}
+
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	for _, r := range p.replicas {
+		if r.name == name {
+			r.weight = weight
+			return true
+		}
+	}
+	return false
+}
+
+// Stats returns statistics of all replicas in the pool.
+func (p *ReplicaPool) Stats() []ReplicaStats {
+	p.m.Lock()
+	replicas := make([]replica, len(p.replicas))
+	for i, r := range p.replicas {
+		replicas[i] = *r
+	}
+	p.m.Unlock()
+
+	// sql.DB.Stats takes its own lock, so call it outside of ours
+	res := make([]ReplicaStats, len(replicas))
+	for i, r := range replicas {
+		res[i] = ReplicaStats{
+			Name:      r.name,
+			Weight:    r.weight,
+			Healthy:   r.healthy,
+			Queries:   r.queries,
+			Errors:    r.errors,
+			Fallbacks: r.fallbacks,
+			Latency:   r.latency,
+			Lag:       r.lag,
+			LastCheck: r.lastCheck,
+			LastError: r.lastError,
+			DBStats:   r.db.Stats(),
+		}
+	}
+	return res
+}
+
+// StartHealthChecks pings all replicas with given timeout, and then starts a background goroutine
+// which does that with given interval. Replicas which fail to respond are marked as unhealthy
+// until the next successful ping.
+// While health checks are running, replicas are also marked as unhealthy after connection-level errors.
+// Queries are sent to master if there are no healthy replicas.
+//
+// Health checks are stopped by StopHealthChecks. Calling StartHealthChecks again restarts them.
+// Both interval and timeout should be positive; otherwise, an error is returned and running health checks
+// are not affected.
+func (p *ReplicaPool) StartHealthChecks(interval, timeout time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("reform: invalid health checks interval %s", interval)
+	}
+	if timeout <= 0 {
+		return fmt.Errorf("reform: invalid health checks timeout %s", timeout)
+	}
+
+	p.StopHealthChecks()
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	p.m.Lock()
+	p.stop, p.done = stop, done
+	p.m.Unlock()
+
+	p.check(timeout)
+
+	go func() {
+		defer close(done)
+
+		t := time.NewTicker(interval)
+		defer t.Stop()
+
+		for {
+			select {
+			case <-t.C:
+				p.check(timeout)
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+// StopHealthChecks stops health checks started by StartHealthChecks and waits for them to finish.
+// All replicas are considered healthy after that.
+func (p *ReplicaPool) StopHealthChecks() {
+	p.m.Lock()
+	stop, done := p.stop, p.done
+	p.stop, p.done = nil, nil
+	p.m.Unlock()
+
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	for _, r := range p.replicas {
+		r.healthy = true
+	}
+}
+
+// check pings all replicas concurrently and updates their health.
+func (p *ReplicaPool) check(timeout time.Duration) {
+	p.m.Lock()
+	replicas := append([]*replica(nil), p.replicas...)
+	maxLag := p.maxLag
+	p.m.Unlock()
+
+	prober, _ := p.dialect.(ReplicationLagProber)
+	if maxLag <= 0 {
+		prober = nil
+	}
+
+	var wg sync.WaitGroup
+	for _, r := range replicas {
+		wg.Add(1)
+		go func(r *replica) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			start := time.Now()
+			err := r.db.PingContext(ctx)
+			d := time.Since(start)
+			var lag time.Duration
+			if err == nil && prober != nil {
+				lag, err = prober.ReplicationLag(ctx, r.db)
+			}
+
+			p.m.Lock()
+			defer p.m.Unlock()
+
+			r.lastCheck = start
+			r.healthy = err == nil
+			if err != nil {
+				r.errors++
+				r.lastError = err
+				return
+			}
+			r.observe(d)
+			if prober != nil {
+				r.lag = lag
+				r.healthy = lag <= maxLag
+			}
+		}(r)
+	}
+	wg.Wait()
+}
+
+// pickForTransaction returns a healthy replica for read-only transaction,
+// or nil if there are none, or if that is disabled.
+func (p *ReplicaPool) pickForTransaction() *replica {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	if p.txMaster {
+		return nil
+	}
+	return p.pickLocked()
+}
+
+// pick returns a healthy replica chosen by pool's selection strategy, or nil if there are none.
+func (p *ReplicaPool) pick() *replica {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	return p.pickLocked()
+}
+
+// pickLocked is pick for callers holding the mutex.
+func (p *ReplicaPool) pickLocked() *replica {
+	healthy := make([]*replica, 0, len(p.replicas))
+	var total int
+	for _, r := range p.replicas {
+		if r.healthy {
+			healthy = append(healthy, r)
+			total += r.weight
+		}
+	}
+	if len(healthy) == 0 {
+		return nil
+	}
+
+	switch p.selection {
+	case WeightedRandom:
+		//nolint:gosec
+		n := rand.Intn(total)
+		for _, r := range healthy {
+			if n < r.weight {
+				return r
+			}
+			n -= r.weight
+		}
+		panic("not reached")
+
+	case RoundRobin:
+		p.next++
+		return healthy[p.next%len(healthy)]
+
+	case LeastLatency:
+		res := healthy[0]
+		for _, r := range healthy[1:] {
+			if r.latency < res.latency {
+				res = r
+			}
+		}
+		return res
+
+	default:
+		panic(fmt.Sprintf("reform: Unhandled ReplicaSelection %s. Please report this bug.", p.selection))
+	}
+}
+
+// record updates replica statistics with the result of the query sent to it,
+// and returns true if query should be resent to master.
+// Statements which may have been executed by replica should be passed with resendable set to false.
+func (p *ReplicaPool) record(r *replica, d time.Duration, err error, resendable bool) bool {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	r.queries++
+	if !isConnectionError(err) {
+		r.observe(d)
+		return false
+	}
+
+	r.errors++
+	r.lastError = err
+	if p.stop != nil {
+		r.healthy = false
+	}
+	if p.fallback && resendable {
+		r.fallbacks++
+		return true
+	}
+	return false
+}
+
+// replicaComment returns SQL comment with replica name for logging transaction commands,
+// or empty string for master.
+func replicaComment(name string) string {
+	if name == "" {
+		return ""
+	}
+	return " /* replica: " + name + " */"
+}
+
+// isNotSentError returns true if err is returned before the statement was sent to SQL database:
+// connection was refused, or driver reported that with driver.ErrBadConn.
+func isNotSentError(err error) bool {
+	return errors.Is(err, driver.ErrBadConn) || errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// isConnectionError returns true if err is caused by broken or refused connection
+// rather than by the query itself.
+func isConnectionError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
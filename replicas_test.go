@@ -0,0 +1,440 @@
+package reform_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mc2soft/reform"
+	"github.com/mc2soft/reform/dialects/postgresql"
+	"github.com/mc2soft/reform/internal/test"
+	"github.com/mc2soft/reform/internal/test/models"
+)
+
+// deadConnector is a driver.Connector for replica which refuses all connections.
+type deadConnector struct{}
+
+func (deadConnector) Connect(context.Context) (driver.Conn, error) {
+	return nil, &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+}
+
+func (c deadConnector) Driver() driver.Driver {
+	return c
+}
+
+func (c deadConnector) Open(string) (driver.Conn, error) {
+	return c.Connect(context.Background())
+}
+
+// brokenConnector is a driver.Connector for replica which connections are broken after sending statements.
+type brokenConnector struct{}
+
+func (brokenConnector) Connect(context.Context) (driver.Conn, error) {
+	return brokenConn{}, nil
+}
+
+func (c brokenConnector) Driver() driver.Driver {
+	return c
+}
+
+func (c brokenConnector) Open(string) (driver.Conn, error) {
+	return c.Connect(context.Background())
+}
+
+// brokenConn is a driver.Conn which returns io.EOF for all statements.
+type brokenConn struct{}
+
+func (brokenConn) Prepare(string) (driver.Stmt, error) { return nil, io.EOF }
+func (brokenConn) Close() error                        { return nil }
+func (brokenConn) Begin() (driver.Tx, error)           { return nil, io.EOF }
+
+func (brokenConn) ExecContext(context.Context, string, []driver.NamedValue) (driver.Result, error) {
+	return nil, io.EOF
+}
+
+func (brokenConn) QueryContext(context.Context, string, []driver.NamedValue) (driver.Rows, error) {
+	return nil, io.EOF
+}
+
+// check interfaces
+var (
+	_ driver.Connector      = deadConnector{}
+	_ driver.Driver         = deadConnector{}
+	_ driver.Connector      = brokenConnector{}
+	_ driver.Driver         = brokenConnector{}
+	_ driver.ExecerContext  = brokenConn{}
+	_ driver.QueryerContext = brokenConn{}
+)
+
+func TestReplicaPool(t *testing.T) {
+	db := setupDB(t)
+	defer teardown(t, db)
+
+	pool := db.Replicas()
+	defer pool.StopHealthChecks()
+
+	aliveDB := test.ConnectToTestDB()
+	defer teardown(t, aliveDB)
+	alive := aliveDB.DBInterface().(*sql.DB)
+	dead := sql.OpenDB(deadConnector{})
+	defer func() {
+		require.NoError(t, dead.Close())
+	}()
+
+	stats := func() map[string]reform.ReplicaStats {
+		res := make(map[string]reform.ReplicaStats)
+		for _, s := range pool.Stats() {
+			res[s.Name] = s
+		}
+		return res
+	}
+	selectOne := func(t *testing.T) error {
+		t.Helper()
+
+		var one int
+		return db.OnReplica().QueryRow("SELECT 1").Scan(&one)
+	}
+
+	t.Run("Manage", func(t *testing.T) {
+		db.AddSlaves(alive, alive)
+		assert.Equal(t, 2, pool.Len())
+		assert.Equal(t, alive, pool.Remove("slave1"))
+		assert.Nil(t, pool.Remove("slave1"))
+		assert.Equal(t, alive, pool.Remove("slave2"))
+
+		require.NoError(t, pool.Add("alive", alive, 2))
+		assert.EqualError(t, pool.Add("alive", alive, 1), `reform: replica "alive" already exists`)
+		assert.EqualError(t, pool.Add("other", alive, 0), `reform: invalid weight 0 for replica "other"`)
+		assert.True(t, pool.SetWeight("alive", 1))
+		assert.False(t, pool.SetWeight("alive", -1))
+		assert.False(t, pool.SetWeight("other", 1))
+		assert.Equal(t, 1, pool.Len())
+
+		s := stats()["alive"]
+		assert.Equal(t, 1, s.Weight)
+		assert.True(t, s.Healthy)
+		assert.Zero(t, s.Queries)
+	})
+
+	t.Run("RoundRobin", func(t *testing.T) {
+		require.NoError(t, pool.Add("alive2", alive, 1))
+		defer pool.Remove("alive2")
+		pool.SetSelection(reform.RoundRobin)
+		defer pool.SetSelection(reform.WeightedRandom)
+
+		before := stats()
+		for i := 0; i < 4; i++ {
+			require.NoError(t, selectOne(t))
+		}
+		after := stats()
+		assert.Equal(t, before["alive"].Queries+2, after["alive"].Queries)
+		assert.Equal(t, before["alive2"].Queries+2, after["alive2"].Queries)
+	})
+
+	t.Run("Fallback", func(t *testing.T) {
+		db.Replicas().Remove("alive")
+		defer func() {
+			require.NoError(t, pool.Add("alive", alive, 1))
+		}()
+		require.NoError(t, pool.Add("dead", dead, 1))
+		defer pool.Remove("dead")
+
+		_, err := db.OnReplica().Query("SELECT 1")
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, syscall.ECONNREFUSED), "%+v", err)
+		var queryErr *reform.QueryError
+		assert.True(t, errors.As(err, &queryErr))
+
+		pool.SetFallback(true)
+		defer pool.SetFallback(false)
+		rows, err := db.OnReplica().Query("SELECT 1")
+		require.NoError(t, err)
+		require.NoError(t, rows.Close())
+
+		// QueryRow is used by SelectOneTo and methods using it
+		var person models.Person
+		require.NoError(t, db.OnReplica().FindByPrimaryKeyTo(&person, 1))
+		assert.Equal(t, int32(1), person.ID)
+		require.NoError(t, db.QuerierForRead().FindByPrimaryKeyTo(&person, 1))
+
+		// statement is resent as it was not sent to refusing replica
+		_, err = db.OnReplica().Exec("DELETE FROM people WHERE id = -1")
+		require.NoError(t, err)
+
+		s := stats()["dead"]
+		assert.True(t, s.Healthy, "replicas are not marked as unhealthy without health checks")
+		assert.Equal(t, int64(5), s.Queries)
+		assert.Equal(t, int64(5), s.Errors)
+		assert.Equal(t, int64(4), s.Fallbacks)
+		assert.True(t, errors.Is(s.LastError, syscall.ECONNREFUSED))
+	})
+
+	t.Run("FallbackExec", func(t *testing.T) {
+		db.Replicas().Remove("alive")
+		defer func() {
+			require.NoError(t, pool.Add("alive", alive, 1))
+		}()
+		broken := sql.OpenDB(brokenConnector{})
+		defer func() {
+			require.NoError(t, broken.Close())
+		}()
+		require.NoError(t, pool.Add("broken", broken, 1))
+		defer pool.Remove("broken")
+
+		pool.SetFallback(true)
+		defer pool.SetFallback(false)
+
+		// statement may be executed by replica before connection is broken, so it is not resent
+		_, err := db.OnReplica().Exec("DELETE FROM people WHERE id = -1")
+		assert.True(t, errors.Is(err, io.EOF), "%+v", err)
+
+		rows, err := db.OnReplica().Query("SELECT 1")
+		require.NoError(t, err)
+		require.NoError(t, rows.Close())
+
+		s := stats()["broken"]
+		assert.Equal(t, int64(2), s.Errors)
+		assert.Equal(t, int64(1), s.Fallbacks)
+	})
+
+	t.Run("HealthChecks", func(t *testing.T) {
+		require.NoError(t, pool.Add("dead", dead, 100))
+		defer pool.Remove("dead")
+
+		assert.EqualError(t, pool.StartHealthChecks(0, 5*time.Second), "reform: invalid health checks interval 0s")
+		assert.EqualError(t, pool.StartHealthChecks(time.Hour, -time.Second), "reform: invalid health checks timeout -1s")
+		assert.True(t, stats()["dead"].Healthy)
+
+		require.NoError(t, pool.StartHealthChecks(time.Hour, 5*time.Second))
+		s := stats()
+		assert.True(t, s["alive"].Healthy)
+		assert.False(t, s["alive"].LastCheck.IsZero())
+		assert.False(t, s["dead"].Healthy)
+		assert.Equal(t, int64(1), s["dead"].Errors)
+
+		// dead replica is skipped despite its weight
+		for i := 0; i < 3; i++ {
+			require.NoError(t, selectOne(t))
+		}
+		assert.Equal(t, s["alive"].Queries+3, stats()["alive"].Queries)
+		assert.Zero(t, stats()["dead"].Queries)
+
+		pool.StopHealthChecks()
+		assert.True(t, stats()["dead"].Healthy)
+	})
+
+	t.Run("NoHealthy", func(t *testing.T) {
+		pool.Remove("alive")
+		defer func() {
+			require.NoError(t, pool.Add("alive", alive, 1))
+		}()
+		require.NoError(t, pool.Add("dead", dead, 1))
+		defer pool.Remove("dead")
+
+		require.NoError(t, pool.StartHealthChecks(time.Hour, 5*time.Second))
+		defer pool.StopHealthChecks()
+
+		// query is sent to master
+		require.NoError(t, selectOne(t))
+		assert.Zero(t, stats()["dead"].Queries)
+		assert.Equal(t, db.Querier, db.QuerierForRead())
+	})
+
+	t.Run("QuerierForRead", func(t *testing.T) {
+		var calls []string
+		db.AddInterceptors(recordingInterceptor{name: "i", calls: &calls})
+
+		q := db.QuerierForRead().WithTag("read")
+		before := stats()["alive"].Queries
+		var person models.Person
+		require.NoError(t, q.FindByPrimaryKeyTo(&person, 1))
+		count, err := q.Count(models.PersonTable, "")
+		require.NoError(t, err)
+		assert.NotZero(t, count)
+
+		assert.Equal(t, before+2, stats()["alive"].Queries)
+		assert.Equal(t, []string{"i QueryRow read alive", "i QueryRow read alive"}, calls)
+	})
+}
+
+// lagDialect is a Dialect with fake replication lag probes.
+type lagDialect struct {
+	reform.Dialect
+	m   sync.Mutex
+	lag map[reform.DBTXContext]time.Duration
+}
+
+func (d *lagDialect) ReplicationLag(ctx context.Context, db reform.DBTXContext) (time.Duration, error) {
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	lag, ok := d.lag[db]
+	if !ok {
+		return 0, errors.New("no lag")
+	}
+	return lag, nil
+}
+
+func (d *lagDialect) setLag(db reform.DBTXContext, lag time.Duration) {
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	d.lag[db] = lag
+}
+
+// check interfaces
+var (
+	_ reform.Dialect              = (*lagDialect)(nil)
+	_ reform.ReplicationLagProber = (*lagDialect)(nil)
+)
+
+func TestReplicaPoolMaxLag(t *testing.T) {
+	master := setupDB(t)
+	defer teardown(t, master)
+
+	if master.Dialect == postgresql.Dialect {
+		lag, err := postgresql.Dialect.ReplicationLag(context.Background(), master.DBInterface())
+		require.NoError(t, err)
+		assert.Zero(t, lag, "master is not a replica")
+	}
+
+	dialect := &lagDialect{Dialect: master.Dialect, lag: make(map[reform.DBTXContext]time.Duration)}
+	db := reform.NewDBFromInterface(master.DBInterface(), dialect, master.Logger)
+	pool := db.Replicas()
+	defer pool.StopHealthChecks()
+
+	replicaDB := test.ConnectToTestDB()
+	defer teardown(t, replicaDB)
+	replica := replicaDB.DBInterface().(*sql.DB)
+	require.NoError(t, pool.Add("replica", replica, 1))
+
+	// lag is not probed by default
+	require.NoError(t, pool.StartHealthChecks(time.Hour, 5*time.Second))
+	assert.True(t, pool.Stats()[0].Healthy)
+
+	pool.SetMaxLag(time.Second)
+	require.NoError(t, pool.StartHealthChecks(time.Hour, 5*time.Second))
+	s := pool.Stats()[0]
+	assert.False(t, s.Healthy)
+	assert.EqualError(t, s.LastError, "no lag")
+
+	dialect.setLag(replica, 500*time.Millisecond)
+	require.NoError(t, pool.StartHealthChecks(time.Hour, 5*time.Second))
+	s = pool.Stats()[0]
+	assert.True(t, s.Healthy)
+	assert.Equal(t, 500*time.Millisecond, s.Lag)
+
+	dialect.setLag(replica, 2*time.Second)
+	require.NoError(t, pool.StartHealthChecks(time.Hour, 5*time.Second))
+	s = pool.Stats()[0]
+	assert.False(t, s.Healthy)
+	assert.Equal(t, 2*time.Second, s.Lag)
+}
+
+// txLogger records transaction commands.
+type txLogger struct {
+	reform.Logger
+	commands []string
+}
+
+func (l *txLogger) After(query string, args []interface{}, d time.Duration, err error) {
+	for _, prefix := range []string{"BEGIN", "COMMIT", "ROLLBACK"} {
+		if strings.HasPrefix(query, prefix) {
+			l.commands = append(l.commands, query)
+		}
+	}
+	l.Logger.After(query, args, d, err)
+}
+
+func TestReadOnlyTransactions(t *testing.T) {
+	db := setupDB(t)
+	defer teardown(t, db)
+
+	logger := &txLogger{Logger: db.Logger}
+	db.Logger = logger
+	pool := db.Replicas()
+
+	replicaDB := test.ConnectToTestDB()
+	defer teardown(t, replicaDB)
+	require.NoError(t, pool.Add("replica", replicaDB.DBInterface().(*sql.DB), 1))
+
+	ctx := context.Background()
+	readOnly := &sql.TxOptions{ReadOnly: true}
+
+	begin := func(t *testing.T, ctx context.Context, opts *sql.TxOptions) string {
+		t.Helper()
+
+		tx, err := db.BeginTx(ctx, opts)
+		require.NoError(t, err)
+		assert.True(t, tx.IsInTransaction())
+		var one int
+		require.NoError(t, tx.QueryRow("SELECT 1").Scan(&one))
+		require.NoError(t, tx.Rollback())
+		return tx.Replica()
+	}
+
+	t.Run("Routing", func(t *testing.T) {
+		logger.commands = nil
+		assert.Equal(t, "replica", begin(t, ctx, readOnly))
+		assert.Equal(t, []string{"BEGIN /* replica: replica */", "ROLLBACK /* replica: replica */"}, logger.commands)
+
+		logger.commands = nil
+		assert.Equal(t, "", begin(t, ctx, nil))
+		assert.Equal(t, "", begin(t, ctx, new(sql.TxOptions)))
+		assert.Equal(t, []string{"BEGIN", "ROLLBACK", "BEGIN", "ROLLBACK"}, logger.commands)
+
+		assert.Equal(t, "", db.Replica())
+		assert.Equal(t, "", db.OnReplica().Replica())
+
+		err := db.InTransactionContext(ctx, readOnly, func(tx *reform.TX) error {
+			assert.Equal(t, "replica", tx.Replica())
+			assert.Equal(t, "replica", tx.WithTag("tag").Replica())
+			return nil
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("OptOut", func(t *testing.T) {
+		pool.SetReadOnlyTransactionsOnMaster(true)
+		assert.Equal(t, "", begin(t, ctx, readOnly))
+		pool.SetReadOnlyTransactionsOnMaster(false)
+		assert.Equal(t, "replica", begin(t, ctx, readOnly))
+
+		rywCtx := reform.WithReadYourWrites(ctx, time.Hour)
+		assert.Equal(t, "replica", begin(t, rywCtx, readOnly))
+		_, err := db.WithContext(rywCtx).Exec("DELETE FROM people WHERE id = -1")
+		require.NoError(t, err)
+		assert.Equal(t, "", begin(t, rywCtx, readOnly))
+	})
+
+	t.Run("Fallback", func(t *testing.T) {
+		alive := pool.Remove("replica")
+		defer func() {
+			require.NoError(t, pool.Add("replica", alive, 1))
+		}()
+		dead := sql.OpenDB(deadConnector{})
+		defer func() {
+			require.NoError(t, dead.Close())
+		}()
+		require.NoError(t, pool.Add("dead", dead, 1))
+		defer pool.Remove("dead")
+
+		_, err := db.BeginTx(ctx, readOnly)
+		assert.True(t, errors.Is(err, syscall.ECONNREFUSED), "%+v", err)
+
+		pool.SetFallback(true)
+		defer pool.SetFallback(false)
+		assert.Equal(t, "", begin(t, ctx, readOnly))
+	})
+}
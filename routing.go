@@ -0,0 +1,274 @@
+package reform
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unicode"
+)
+
+// Route is a destination of query made by Querier outside of transaction when DB has slaves.
+type Route int
+
+// Routes.
+const (
+	RouteMaster  Route = iota + 1 // master connection
+	RouteReplica                  // random slave connection
+)
+
+// RoutingPolicy decides where queries made by Querier outside of transaction are sent when DB has slaves
+// (see DB.AddSlaves). Queries made inside transaction are always sent to transaction's connection.
+// Returned values other than RouteMaster and RouteReplica are treated as RouteMaster.
+type RoutingPolicy interface {
+	Route(query string) Route
+}
+
+// RoutingPolicyFunc is an adapter to allow the use of ordinary functions as RoutingPolicy.
+type RoutingPolicyFunc func(query string) Route
+
+// Route returns f(query).
+func (f RoutingPolicyFunc) Route(query string) Route {
+	return f(query)
+}
+
+// readYourWrites stores the time until which reads made with context are sent to master.
+type readYourWrites struct {
+	window time.Duration
+	until  atomic.Int64 // UnixNano
+}
+
+// wrote extends the period of reading from master.
+func (r *readYourWrites) wrote() {
+	r.until.Store(time.Now().Add(r.window).UnixNano())
+}
+
+// active returns true if reads should be sent to master. It is safe to call on nil.
+func (r *readYourWrites) active() bool {
+	return r != nil && time.Now().UnixNano() < r.until.Load()
+}
+
+type readYourWritesKey struct{}
+
+// WithReadYourWrites returns a copy of parent context which makes Queriers send queries which would be routed
+// to replicas (see RoutingPolicy) to master instead for a given window after any query which would not be
+// (typically INSERT, UPDATE or DELETE) is made with that context or contexts derived from it,
+// including queries in transactions. That allows to read own writes despite replication lag.
+// Querier's OnReplica overrides that.
+//
+// Typically, it is used once per HTTP request or other unit of work.
+func WithReadYourWrites(parent context.Context, window time.Duration) context.Context {
+	return context.WithValue(parent, readYourWritesKey{}, &readYourWrites{window: window})
+}
+
+// readYourWritesFrom returns state stored by WithReadYourWrites, or nil.
+func readYourWritesFrom(ctx context.Context) *readYourWrites {
+	r, _ := ctx.Value(readYourWritesKey{}).(*readYourWrites)
+	return r
+}
+
+// DefaultRoutingPolicy sends read-only queries to replicas, and everything else to master.
+// Query is considered read-only if it is a single SELECT statement (possibly with CTEs,
+// in parentheses, prefixed with comments or tag) without:
+//   - locking clauses (FOR UPDATE, FOR SHARE, FOR NO KEY UPDATE, FOR KEY SHARE, LOCK IN SHARE MODE,
+//     UPDLOCK, HOLDLOCK and XLOCK table hints);
+//   - data-modifying CTEs (WITH ... INSERT/UPDATE/DELETE/MERGE) and SELECT ... INTO;
+//   - calls of volatile functions (nextval, setval, last_insert_id, get_lock, pg_advisory_lock, etc).
+//
+// Keywords and function names are case-insensitive; string literals, quoted identifiers
+// and comments are ignored.
+type DefaultRoutingPolicy struct {
+	// VolatileFunctions contains additional names of functions that make SELECT query
+	// a write or depend on session state, so it should be sent to master.
+	VolatileFunctions []string
+}
+
+//nolint:gochecknoglobals
+var defaultVolatileFunctions = map[string]struct{}{
+	// PostgreSQL
+	"nextval":                   {},
+	"setval":                    {},
+	"currval":                   {},
+	"lastval":                   {},
+	"txid_current":              {},
+	"pg_current_xact_id":        {},
+	"pg_advisory_lock":          {},
+	"pg_advisory_lock_shared":   {},
+	"pg_advisory_xact_lock":     {},
+	"pg_try_advisory_lock":      {},
+	"pg_try_advisory_xact_lock": {},
+	"pg_advisory_unlock":        {},
+	"pg_advisory_unlock_all":    {},
+
+	// MySQL
+	"last_insert_id":    {},
+	"found_rows":        {},
+	"row_count":         {},
+	"get_lock":          {},
+	"release_lock":      {},
+	"release_all_locks": {},
+
+	// SQLite3
+	"last_insert_rowid": {},
+	"changes":           {},
+
+	// SQL Server
+	"scope_identity": {},
+	"ident_current":  {},
+}
+
+// Route returns RouteReplica for read-only queries, and RouteMaster otherwise.
+func (p DefaultRoutingPolicy) Route(query string) Route {
+	tokens := sqlTokens(query)
+
+	i := 0
+	for i < len(tokens) && tokens[i] == "(" {
+		i++
+	}
+	if i == len(tokens) || (tokens[i] != "select" && tokens[i] != "with") {
+		return RouteMaster
+	}
+
+	for ; i < len(tokens); i++ {
+		var next string
+		if i+1 < len(tokens) {
+			next = tokens[i+1]
+		}
+
+		switch tokens[i] {
+		case ";":
+			if next != "" && next != ";" {
+				return RouteMaster // multiple statements
+			}
+		case "insert", "update", "delete", "merge", "into", "updlock", "holdlock", "xlock":
+			return RouteMaster
+		case "for":
+			if next == "share" || next == "key" {
+				return RouteMaster
+			}
+		case "lock":
+			if next == "in" {
+				return RouteMaster
+			}
+		case "next":
+			if next == "value" {
+				return RouteMaster // SQL Server's NEXT VALUE FOR sequence
+			}
+		default:
+			if next == "(" && p.isVolatile(tokens[i]) {
+				return RouteMaster
+			}
+		}
+	}
+
+	return RouteReplica
+}
+
+// isVolatile returns true if given lowercase (possibly qualified) function name is volatile.
+func (p DefaultRoutingPolicy) isVolatile(name string) bool {
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		name = name[i+1:]
+	}
+	if _, ok := defaultVolatileFunctions[name]; ok {
+		return true
+	}
+	for _, f := range p.VolatileFunctions {
+		if strings.EqualFold(f, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// sqlTokens splits query into lowercase words (including qualified names), "(" and ";" tokens.
+// Other punctuation, string literals, quoted identifiers, placeholders and comments are skipped.
+func sqlTokens(query string) []string {
+	var res []string
+	s := query
+	for len(s) > 0 {
+		c := s[0]
+		switch {
+		case c == '-' && strings.HasPrefix(s, "--"):
+			s = skipUntil(s[2:], "\n")
+
+		case c == '/' && strings.HasPrefix(s, "/*"):
+			s = skipUntil(s[2:], "*/")
+
+		case c == '\'' || c == '"' || c == '`':
+			s = skipQuoted(s[1:], c)
+
+		case c == '[':
+			s = skipUntil(s[1:], "]")
+
+		case c == '$':
+			// PostgreSQL's dollar-quoted string or placeholder
+			end := strings.IndexByte(s[1:], '$')
+			if end >= 0 && isIdentifier(s[1:end+1]) {
+				s = skipUntil(s[end+2:], s[:end+2])
+			} else {
+				s = s[1:]
+			}
+
+		case c == '(' || c == ';':
+			res = append(res, string(c))
+			s = s[1:]
+
+		case isWordByte(c):
+			end := 1
+			for end < len(s) && (isWordByte(s[end]) || s[end] == '.') {
+				end++
+			}
+			res = append(res, strings.ToLower(s[:end]))
+			s = s[end:]
+
+		default:
+			s = s[1:]
+		}
+	}
+	return res
+}
+
+// skipUntil returns s after the first occurrence of end, or empty string if there is none.
+func skipUntil(s, end string) string {
+	i := strings.Index(s, end)
+	if i < 0 {
+		return ""
+	}
+	return s[i+len(end):]
+}
+
+// skipQuoted returns s after closing quote; doubled quotes are skipped.
+func skipQuoted(s string, quote byte) string {
+	for i := 0; i < len(s); i++ {
+		if s[i] != quote {
+			continue
+		}
+		if i+1 < len(s) && s[i+1] == quote {
+			i++
+			continue
+		}
+		return s[i+1:]
+	}
+	return ""
+}
+
+// isWordByte returns true if c can be a part of SQL keyword or identifier.
+func isWordByte(c byte) bool {
+	return c == '_' || c == '@' || c >= 0x80 || unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c))
+}
+
+// isIdentifier returns true if s is empty or a valid dollar-quoted string tag.
+func isIdentifier(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !isWordByte(s[i]) || (i == 0 && unicode.IsDigit(rune(s[i]))) {
+			return false
+		}
+	}
+	return true
+}
+
+// check interfaces
+var (
+	_ RoutingPolicy = DefaultRoutingPolicy{}
+	_ RoutingPolicy = RoutingPolicyFunc(nil)
+)
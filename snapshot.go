@@ -0,0 +1,116 @@
+package reform
+
+import (
+	"reflect"
+	"time"
+)
+
+// Snapshot stores values of struct's fields for dirty tracking.
+// Embed it (without "reform:" tag) into struct to enable tracking:
+//
+//	//reform:people
+//	type Person struct {
+//		reform.Snapshot
+//		ID   int32  `reform:"id,pk"`
+//		Name string `reform:"name"`
+//	}
+//
+// Querier takes a snapshot after loading struct (after AfterFind()), and after successful Insert, InsertColumns,
+// InsertMulti, Update, UpdateChanged, Upsert and UpsertMulti; UpdateColumns updates snapshot only for given columns.
+// Querier.UpdateChanged and generated Changed method compare struct's values with that snapshot.
+type Snapshot struct {
+	values []interface{}
+}
+
+func (s *Snapshot) snapshot() *Snapshot {
+	return s
+}
+
+// snapshotter is implemented by structs embedding Snapshot.
+type snapshotter interface {
+	snapshot() *Snapshot
+}
+
+// snapshotValue returns a copy of value suitable for storing in snapshot:
+// pointers are dereferenced (nil pointers are stored as nil), and slices are copied.
+func snapshotValue(value interface{}) interface{} {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		return snapshotValue(v.Elem().Interface())
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return value
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		reflect.Copy(c, v)
+		return c.Interface()
+
+	default:
+		return value
+	}
+}
+
+// snapshotEqual returns true if snapshot values are equal; time.Time values are compared with Equal.
+func snapshotEqual(a, b interface{}) bool {
+	if ta, ok := a.(time.Time); ok {
+		tb, ok := b.(time.Time)
+		return ok && ta.Equal(tb)
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// takeSnapshot stores values of all str's fields in its Snapshot, if it is embedded.
+func takeSnapshot(str Struct) {
+	s, ok := str.(snapshotter)
+	if !ok {
+		return
+	}
+
+	values := str.Values()
+	for i, v := range values {
+		values[i] = snapshotValue(v)
+	}
+	s.snapshot().values = values
+}
+
+// updateSnapshot stores values of given str's columns in its Snapshot, if it is embedded and was taken before.
+func updateSnapshot(str Struct, columns []string) {
+	s, ok := str.(snapshotter)
+	if !ok || s.snapshot().values == nil {
+		return
+	}
+
+	values := str.Values()
+	for i, c := range str.View().Columns() {
+		for _, column := range columns {
+			if c == column {
+				s.snapshot().values[i] = snapshotValue(values[i])
+				break
+			}
+		}
+	}
+}
+
+// ChangedColumns returns names of str's columns which values differ from its Snapshot.
+// If str doesn't embed Snapshot, or snapshot was not taken yet, it returns all columns.
+// Generated Changed methods of records call it.
+func ChangedColumns(str Struct) []string {
+	columns := str.View().Columns()
+	s, ok := str.(snapshotter)
+	if !ok || s.snapshot().values == nil {
+		return columns
+	}
+
+	res := make([]string, 0, len(columns))
+	for i, v := range str.Values() {
+		if !snapshotEqual(s.snapshot().values[i], snapshotValue(v)) {
+			res = append(res, columns[i])
+		}
+	}
+	return res
+}
@@ -30,5 +30,21 @@ CREATE TABLE constraints (
   UNIQUE ([i])
 );
 
+CREATE TABLE composite_pk (
+  [i] int NOT NULL,
+  [name] varchar(255) NOT NULL,
+  [id] varchar(255) NOT NULL,
+  PRIMARY KEY ([i], [id])
+);
+
+CREATE TABLE documents (
+  [id] int identity(1, 1) PRIMARY KEY,
+  [name] varchar(255) NOT NULL,
+  [lock_version] bigint NOT NULL,
+  [deleted_at] datetime2,
+  [created_at] datetime2 NOT NULL,
+  [updated_at] datetime2
+);
+
 -- to allow insert test data with IDs
 SET IDENTITY_INSERT people ON;
@@ -43,3 +43,20 @@ CREATE TABLE constraints (
   PRIMARY KEY (id),
   UNIQUE (i)
 );
+
+CREATE TABLE composite_pk (
+  i int NOT NULL,
+  name varchar(255) NOT NULL,
+  id varchar(255) NOT NULL,
+  PRIMARY KEY (i, id)
+);
+
+CREATE TABLE documents (
+  id int NOT NULL AUTO_INCREMENT,
+  name varchar(255) NOT NULL,
+  lock_version bigint NOT NULL,
+  deleted_at datetime,
+  created_at datetime NOT NULL,
+  updated_at datetime,
+  PRIMARY KEY (id)
+);
@@ -32,6 +32,22 @@ CREATE TABLE constraints (
   UNIQUE (i)
 );
 
+CREATE TABLE composite_pk (
+  i integer NOT NULL,
+  name varchar NOT NULL,
+  id varchar NOT NULL,
+  PRIMARY KEY (i, id)
+);
+
+CREATE TABLE documents (
+  id serial PRIMARY KEY,
+  name varchar NOT NULL,
+  lock_version bigint NOT NULL,
+  deleted_at timestamp with time zone,
+  created_at timestamp with time zone NOT NULL,
+  updated_at timestamp with time zone
+);
+
 CREATE SCHEMA legacy;
 
 CREATE TABLE legacy.people (
@@ -29,3 +29,19 @@ CREATE TABLE constraints (
   id varchar NOT NULL PRIMARY KEY,
   UNIQUE (i)
 );
+
+CREATE TABLE composite_pk (
+  i integer NOT NULL,
+  name varchar NOT NULL,
+  id varchar NOT NULL,
+  PRIMARY KEY (i, id)
+);
+
+CREATE TABLE documents (
+  id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+  name varchar NOT NULL,
+  lock_version bigint NOT NULL,
+  deleted_at datetime,
+  created_at datetime NOT NULL,
+  updated_at datetime
+);
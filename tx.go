@@ -3,6 +3,7 @@ package reform
 import (
 	"context"
 	"database/sql"
+	"strconv"
 	"time"
 )
 
@@ -26,7 +27,8 @@ var _ TXInterface = (*sql.Tx)(nil)
 // TX represents a SQL database transaction.
 type TX struct {
 	*Querier
-	tx TXInterface
+	tx         TXInterface
+	savepoints int // the number of active savepoints created by InTransaction
 }
 
 // NewTX creates new TX object for given SQL database transaction.
@@ -50,20 +52,115 @@ func newTX(ctx context.Context, tx TXInterface, dialect Dialect, logger Logger)
 }
 
 // Commit commits the transaction.
+// Errors, except ErrTxDone, are wrapped into QueryError.
 func (tx *TX) Commit() error {
-	tx.logBefore("COMMIT", nil)
-	start := time.Now()
-	err := tx.tx.Commit()
-	tx.logAfter("COMMIT", nil, time.Since(start), err)
-	return err
+	return tx.finish(OpCommit, "COMMIT", tx.tx.Commit)
 }
 
 // Rollback aborts the transaction.
+// Errors, except ErrTxDone, are wrapped into QueryError.
 func (tx *TX) Rollback() error {
-	tx.logBefore("ROLLBACK", nil)
-	start := time.Now()
-	err := tx.tx.Rollback()
-	tx.logAfter("ROLLBACK", nil, time.Since(start), err)
+	return tx.finish(OpRollback, "ROLLBACK", tx.tx.Rollback)
+}
+
+// finish commits or rolls back the transaction with given function via interceptors.
+func (tx *TX) finish(op Operation, command string, f func() error) error {
+	res := tx.intercept(tx.newCall(op, command, nil, nil), func(_ context.Context, call Call) CallResult {
+		query := call.Query + replicaComment(tx.replica)
+		tx.logBefore(query, call.Args)
+		start := time.Now()
+
+		err := f()
+		tx.logAfter(query, call.Args, time.Since(start), err)
+		if err != ErrTxDone {
+			err = tx.wrapError(call.Query, err)
+		}
+		return CallResult{Err: err}
+	})
+	return res.Err
+}
+
+// Savepoint creates a savepoint with given name in the transaction.
+func (tx *TX) Savepoint(name string) error {
+	var query string
+	switch tx.SavepointMethod() {
+	case Savepoint:
+		query = "SAVEPOINT " + tx.QuoteIdentifier(name)
+	case SaveTransaction:
+		query = "SAVE TRANSACTION " + tx.QuoteIdentifier(name)
+	default:
+		panic("reform: Unhandled SavepointMethod. Please report this bug.")
+	}
+
+	_, err := tx.Exec(query)
+	return err
+}
+
+// RollbackTo rolls back the transaction to the savepoint with given name.
+// The savepoint stays active.
+func (tx *TX) RollbackTo(name string) error {
+	var query string
+	switch tx.SavepointMethod() {
+	case Savepoint:
+		query = "ROLLBACK TO SAVEPOINT " + tx.QuoteIdentifier(name)
+	case SaveTransaction:
+		query = "ROLLBACK TRANSACTION " + tx.QuoteIdentifier(name)
+	default:
+		panic("reform: Unhandled SavepointMethod. Please report this bug.")
+	}
+
+	_, err := tx.Exec(query)
+	return err
+}
+
+// Release releases the savepoint with given name, keeping changes made after it.
+// For dialects with SaveTransaction savepoint method, it does nothing.
+func (tx *TX) Release(name string) error {
+	switch tx.SavepointMethod() {
+	case Savepoint:
+		_, err := tx.Exec("RELEASE SAVEPOINT " + tx.QuoteIdentifier(name))
+		return err
+	case SaveTransaction:
+		return nil
+	default:
+		panic("reform: Unhandled SavepointMethod. Please report this bug.")
+	}
+}
+
+// InTransaction wraps function execution in a savepoint of the transaction,
+// rolling back to it in case of error or panic, releasing it otherwise.
+// Function receives the same TX, so calls can be nested.
+// Functions added with AddOnCommitCall are kept until the outermost commit by DB.InTransaction,
+// or dropped if the savepoint is rolled back.
+func (tx *TX) InTransaction(f func(t *TX) error) error {
+	tx.savepoints++
+	defer func() {
+		tx.savepoints--
+	}()
+	name := "reform_savepoint_" + strconv.Itoa(tx.savepoints)
+
+	if err := tx.Savepoint(name); err != nil {
+		return err
+	}
+
+	onCommitCalls := len(tx.Querier.onCommitCalls)
+	var released bool
+	defer func() {
+		if !released {
+			// always return f() or Release() error, not possible RollbackTo() error
+			_ = tx.RollbackTo(name)
+			_ = tx.Release(name)
+			tx.Querier.onCommitCalls = tx.Querier.onCommitCalls[:onCommitCalls]
+		}
+	}()
+
+	err := f(tx)
+	if err == nil {
+		err = tx.Release(name)
+	}
+	if err == nil {
+		released = true
+	}
 	return err
 }
 
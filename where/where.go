@@ -0,0 +1,311 @@
+// Package where implements typed query tails for reform.
+//
+// Conditions and tails are built from generated column descriptors and rendered with dialect's
+// placeholders and quoting. They can be passed to Querier methods accepting tail and args
+// as a single argument:
+//
+//	persons, err := q.SelectAllFrom(PersonTable, "", where.Where(
+//		where.Eq(PersonTable.C.GroupID, 1),
+//		where.Like(PersonTable.C.Email, "%@example.com"),
+//	).OrderBy(PersonTable.C.Name).Limit(10))
+package where
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/mc2soft/reform"
+)
+
+// builder accumulates query tail text and arguments.
+type builder struct {
+	dialect reform.Dialect
+	start   int
+	sb      strings.Builder
+	args    []interface{}
+}
+
+// column writes quoted column name.
+func (b *builder) column(c reform.Column) {
+	b.sb.WriteString(b.dialect.QuoteIdentifier(string(c)))
+}
+
+// arg writes placeholder for given argument.
+func (b *builder) arg(arg interface{}) {
+	b.sb.WriteString(b.dialect.Placeholder(b.start + len(b.args)))
+	b.args = append(b.args, arg)
+}
+
+// Cond is a condition of WHERE clause.
+// It also implements reform.TailExpression and renders into WHERE clause.
+type Cond interface {
+	reform.TailExpression
+
+	writeCond(b *builder)
+}
+
+// cond implements Cond with a function writing it.
+type cond struct {
+	write func(b *builder)
+}
+
+func (c cond) writeCond(b *builder) {
+	c.write(b)
+}
+
+// Tail returns WHERE clause with that condition for given dialect with placeholders starting from given index,
+// and arguments for them.
+func (c cond) Tail(dialect reform.Dialect, start int) (string, []interface{}) {
+	return Where(c).Tail(dialect, start)
+}
+
+// binary returns condition comparing column and value with given operator.
+func binary(column reform.Column, op string, value interface{}) Cond {
+	return cond{func(b *builder) {
+		b.column(column)
+		b.sb.WriteString(" " + op + " ")
+		b.arg(value)
+	}}
+}
+
+// Eq returns "column = value" condition.
+// Use IsNull for comparison with NULL.
+func Eq(column reform.Column, value interface{}) Cond {
+	return binary(column, "=", value)
+}
+
+// Ne returns "column <> value" condition.
+// Use IsNotNull for comparison with NULL.
+func Ne(column reform.Column, value interface{}) Cond {
+	return binary(column, "<>", value)
+}
+
+// Lt returns "column < value" condition.
+func Lt(column reform.Column, value interface{}) Cond {
+	return binary(column, "<", value)
+}
+
+// Le returns "column <= value" condition.
+func Le(column reform.Column, value interface{}) Cond {
+	return binary(column, "<=", value)
+}
+
+// Gt returns "column > value" condition.
+func Gt(column reform.Column, value interface{}) Cond {
+	return binary(column, ">", value)
+}
+
+// Ge returns "column >= value" condition.
+func Ge(column reform.Column, value interface{}) Cond {
+	return binary(column, ">=", value)
+}
+
+// Like returns "column LIKE pattern" condition.
+func Like(column reform.Column, pattern string) Cond {
+	return binary(column, "LIKE", pattern)
+}
+
+// In returns "column IN (values)" condition.
+// Without values, it is always false.
+func In(column reform.Column, values ...interface{}) Cond {
+	return cond{func(b *builder) {
+		if len(values) == 0 {
+			b.sb.WriteString("1 = 0")
+			return
+		}
+
+		b.column(column)
+		b.sb.WriteString(" IN (")
+		for i, v := range values {
+			if i > 0 {
+				b.sb.WriteString(", ")
+			}
+			b.arg(v)
+		}
+		b.sb.WriteString(")")
+	}}
+}
+
+// IsNull returns "column IS NULL" condition.
+func IsNull(column reform.Column) Cond {
+	return cond{func(b *builder) {
+		b.column(column)
+		b.sb.WriteString(" IS NULL")
+	}}
+}
+
+// IsNotNull returns "column IS NOT NULL" condition.
+func IsNotNull(column reform.Column) Cond {
+	return cond{func(b *builder) {
+		b.column(column)
+		b.sb.WriteString(" IS NOT NULL")
+	}}
+}
+
+// Not returns "NOT (condition)" condition.
+func Not(c Cond) Cond {
+	return cond{func(b *builder) {
+		b.sb.WriteString("NOT (")
+		c.writeCond(b)
+		b.sb.WriteString(")")
+	}}
+}
+
+// join returns conditions joined with given operator in parentheses, or empty condition if there are none.
+func join(op string, empty string, conds []Cond) Cond {
+	return cond{func(b *builder) {
+		switch len(conds) {
+		case 0:
+			b.sb.WriteString(empty)
+		case 1:
+			conds[0].writeCond(b)
+		default:
+			b.sb.WriteString("(")
+			for i, c := range conds {
+				if i > 0 {
+					b.sb.WriteString(" " + op + " ")
+				}
+				c.writeCond(b)
+			}
+			b.sb.WriteString(")")
+		}
+	}}
+}
+
+// And returns condition which is true if all given conditions are true.
+// Without conditions, it is always true.
+func And(conds ...Cond) Cond {
+	return join("AND", "1 = 1", conds)
+}
+
+// Or returns condition which is true if any of given conditions is true.
+// Without conditions, it is always false.
+func Or(conds ...Cond) Cond {
+	return join("OR", "1 = 0", conds)
+}
+
+// order is an item of ORDER BY clause.
+type order struct {
+	column reform.Column
+	desc   bool
+}
+
+// Tail represents query tail with WHERE, ORDER BY, and LIMIT/OFFSET clauses.
+// Its methods modify and return the same Tail.
+type Tail struct {
+	where  Cond
+	orders []order
+	limit  int
+	offset int
+}
+
+// Where returns Tail with WHERE clause with conditions joined by AND.
+func Where(conds ...Cond) *Tail {
+	t := new(Tail)
+	if len(conds) > 0 {
+		t.where = And(conds...)
+	}
+	return t
+}
+
+// OrderBy returns Tail without WHERE clause ordered by given columns in ascending order.
+func OrderBy(columns ...reform.Column) *Tail {
+	return Where().OrderBy(columns...)
+}
+
+// OrderBy adds given columns in ascending order to ORDER BY clause.
+func (t *Tail) OrderBy(columns ...reform.Column) *Tail {
+	for _, c := range columns {
+		t.orders = append(t.orders, order{column: c})
+	}
+	return t
+}
+
+// OrderByDesc adds given columns in descending order to ORDER BY clause.
+func (t *Tail) OrderByDesc(columns ...reform.Column) *Tail {
+	for _, c := range columns {
+		t.orders = append(t.orders, order{column: c, desc: true})
+	}
+	return t
+}
+
+// Limit sets the maximum number of rows. Zero or negative value removes limit.
+func (t *Tail) Limit(limit int) *Tail {
+	t.limit = limit
+	return t
+}
+
+// Offset sets the number of rows to skip. Zero or negative value removes offset.
+func (t *Tail) Offset(offset int) *Tail {
+	t.offset = offset
+	return t
+}
+
+// Tail returns query tail for given dialect with placeholders starting from given index,
+// and arguments for them.
+//
+// For dialects with SelectTop limit method, LIMIT and OFFSET are rendered as
+// "OFFSET ... ROWS FETCH NEXT ... ROWS ONLY", which requires ORDER BY clause; if it is absent,
+// "ORDER BY (SELECT NULL)" is used.
+func (t *Tail) Tail(dialect reform.Dialect, start int) (string, []interface{}) {
+	b := &builder{
+		dialect: dialect,
+		start:   start,
+	}
+
+	var parts []string
+	if t.where != nil {
+		t.where.writeCond(b)
+		parts = append(parts, "WHERE "+b.sb.String())
+	}
+
+	limited := t.limit > 0 || t.offset > 0
+	if len(t.orders) > 0 {
+		orders := make([]string, len(t.orders))
+		for i, o := range t.orders {
+			orders[i] = dialect.QuoteIdentifier(string(o.column))
+			if o.desc {
+				orders[i] += " DESC"
+			}
+		}
+		parts = append(parts, "ORDER BY "+strings.Join(orders, ", "))
+	} else if limited && dialect.SelectLimitMethod() == reform.SelectTop {
+		parts = append(parts, "ORDER BY (SELECT NULL)")
+	}
+
+	if limited {
+		switch dialect.SelectLimitMethod() {
+		case reform.Limit:
+			limit := int64(math.MaxInt64)
+			if t.limit > 0 {
+				limit = int64(t.limit)
+			}
+			parts = append(parts, "LIMIT "+strconv.FormatInt(limit, 10))
+			if t.offset > 0 {
+				parts = append(parts, "OFFSET "+strconv.Itoa(t.offset))
+			}
+
+		case reform.SelectTop:
+			offset := t.offset
+			if offset < 0 {
+				offset = 0
+			}
+			parts = append(parts, "OFFSET "+strconv.Itoa(offset)+" ROWS")
+			if t.limit > 0 {
+				parts = append(parts, "FETCH NEXT "+strconv.Itoa(t.limit)+" ROWS ONLY")
+			}
+
+		default:
+			panic("reform: Unhandled SelectLimitMethod. Please report this bug.")
+		}
+	}
+
+	return strings.Join(parts, " "), b.args
+}
+
+// check interfaces
+var (
+	_ Cond                  = cond{}
+	_ reform.TailExpression = (*Tail)(nil)
+)
@@ -0,0 +1,100 @@
+package where
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mc2soft/reform"
+	"github.com/mc2soft/reform/dialects/mysql"
+	"github.com/mc2soft/reform/dialects/postgresql"
+	"github.com/mc2soft/reform/dialects/sqlserver"
+)
+
+func TestTail(t *testing.T) {
+	const (
+		id    reform.Column = "id"
+		name  reform.Column = "name"
+		email reform.Column = "email"
+	)
+
+	for _, tc := range []struct {
+		name     string
+		expr     reform.TailExpression
+		dialect  reform.Dialect
+		start    int
+		expected string
+		args     []interface{}
+	}{{
+		name:     "Eq",
+		expr:     Eq(id, 1),
+		dialect:  postgresql.Dialect,
+		start:    1,
+		expected: `WHERE "id" = $1`,
+		args:     []interface{}{1},
+	}, {
+		name:     "EqStart",
+		expr:     Eq(id, 1),
+		dialect:  postgresql.Dialect,
+		start:    3,
+		expected: `WHERE "id" = $3`,
+		args:     []interface{}{1},
+	}, {
+		name: "AndOr",
+		expr: Where(
+			Or(Like(name, "A%"), IsNull(email)),
+			In(id, 1, 2, 3),
+			Not(Eq(id, 4)),
+		),
+		dialect:  postgresql.Dialect,
+		start:    1,
+		expected: `WHERE (("name" LIKE $1 OR "email" IS NULL) AND "id" IN ($2, $3, $4) AND NOT ("id" = $5))`,
+		args:     []interface{}{"A%", 1, 2, 3, 4},
+	}, {
+		name:     "Comparisons",
+		expr:     And(Ne(id, 1), Lt(id, 2), Le(id, 3), Gt(id, 4), Ge(id, 5), IsNotNull(email)),
+		dialect:  mysql.Dialect,
+		start:    1,
+		expected: "WHERE (`id` <> ? AND `id` < ? AND `id` <= ? AND `id` > ? AND `id` >= ? AND `email` IS NOT NULL)",
+		args:     []interface{}{1, 2, 3, 4, 5},
+	}, {
+		name:     "Empty",
+		expr:     Where(In(id), And(), Or()),
+		dialect:  mysql.Dialect,
+		start:    1,
+		expected: "WHERE (1 = 0 AND 1 = 1 AND 1 = 0)",
+	}, {
+		name:     "OrderByLimitOffset",
+		expr:     Where(Eq(id, 1)).OrderBy(name).OrderByDesc(id).Limit(10).Offset(20),
+		dialect:  mysql.Dialect,
+		start:    1,
+		expected: "WHERE `id` = ? ORDER BY `name`, `id` DESC LIMIT 10 OFFSET 20",
+		args:     []interface{}{1},
+	}, {
+		name:     "Offset",
+		expr:     OrderBy(name).Offset(20),
+		dialect:  postgresql.Dialect,
+		start:    1,
+		expected: `ORDER BY "name" LIMIT 9223372036854775807 OFFSET 20`,
+	}, {
+		name:     "SelectTop",
+		expr:     Where(Eq(id, 1)).OrderBy(name).Limit(10).Offset(20),
+		dialect:  sqlserver.Dialect,
+		start:    1,
+		expected: `WHERE [id] = @P1 ORDER BY [name] OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY`,
+		args:     []interface{}{1},
+	}, {
+		name:     "SelectTopWithoutOrder",
+		expr:     Where().Limit(10),
+		dialect:  sqlserver.Dialect,
+		start:    1,
+		expected: `ORDER BY (SELECT NULL) OFFSET 0 ROWS FETCH NEXT 10 ROWS ONLY`,
+	}} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			actual, args := tc.expr.Tail(tc.dialect, tc.start)
+			assert.Equal(t, tc.expected, actual)
+			assert.Equal(t, tc.args, args)
+		})
+	}
+}